@@ -0,0 +1,167 @@
+package configuration
+
+import (
+	"fmt"
+	"strings"
+)
+
+type TableRef struct {
+	Schema string
+	Table  string
+}
+
+func ParseTableRef(reference string) (TableRef, error) {
+	identifiers, err := splitIdentifiers(reference)
+	if err != nil {
+		return TableRef{}, err
+	}
+
+	switch len(identifiers) {
+	case 1:
+		return TableRef{Table: identifiers[0]}, nil
+	case 2:
+		return TableRef{Schema: identifiers[0], Table: identifiers[1]}, nil
+	}
+
+	return TableRef{}, fmt.Errorf("table reference %q must be of the form [schema.]table", reference)
+}
+
+func (tableRef TableRef) String() string {
+	return joinIdentifiers(tableRef.Schema, tableRef.Table)
+}
+
+func (tableRef TableRef) Matches(other TableRef) bool {
+	if tableRef.Table != other.Table {
+		return false
+	}
+
+	return tableRef.Schema == "" || other.Schema == "" || tableRef.Schema == other.Schema
+}
+
+type ColumnRef struct {
+	Schema string
+	Table  string
+	Column string
+}
+
+func ParseColumnRef(reference string) (ColumnRef, error) {
+	identifiers, err := splitIdentifiers(reference)
+	if err != nil {
+		return ColumnRef{}, err
+	}
+
+	switch len(identifiers) {
+	case 2:
+		return ColumnRef{Table: identifiers[0], Column: identifiers[1]}, nil
+	case 3:
+		return ColumnRef{Schema: identifiers[0], Table: identifiers[1], Column: identifiers[2]}, nil
+	}
+
+	return ColumnRef{}, fmt.Errorf("column reference %q must be of the form [schema.]table.column", reference)
+}
+
+func parseColumnRefs(references []string) ([]ColumnRef, error) {
+	columnRefs := make([]ColumnRef, 0, len(references))
+	for _, reference := range references {
+		columnRef, err := ParseColumnRef(reference)
+		if err != nil {
+			return nil, err
+		}
+
+		columnRefs = append(columnRefs, columnRef)
+	}
+
+	return columnRefs, nil
+}
+
+func (columnRef ColumnRef) TableRef() TableRef {
+	return TableRef{Schema: columnRef.Schema, Table: columnRef.Table}
+}
+
+func (columnRef ColumnRef) String() string {
+	return joinIdentifiers(columnRef.Schema, columnRef.Table, columnRef.Column)
+}
+
+func splitIdentifiers(reference string) ([]string, error) {
+	var identifiers []string
+	var identifier strings.Builder
+	quoted := false
+	closed := false
+	var closingQuote rune
+
+	runes := []rune(strings.TrimSpace(reference))
+	for index := 0; index < len(runes); index++ {
+		character := runes[index]
+		switch {
+		case quoted && character == closingQuote:
+			if index+1 < len(runes) && runes[index+1] == closingQuote {
+				identifier.WriteRune(character)
+				index++
+				continue
+			}
+			quoted = false
+			closed = true
+
+		case quoted:
+			identifier.WriteRune(character)
+
+		case character == '.':
+			if identifier.Len() == 0 {
+				return nil, fmt.Errorf("identifier %q has an empty name", reference)
+			}
+			identifiers = append(identifiers, identifier.String())
+			identifier.Reset()
+			closed = false
+
+		case closed:
+			return nil, fmt.Errorf("identifier %q has characters after a closing quote", reference)
+
+		case character == '"' || character == '`':
+			if identifier.Len() > 0 {
+				return nil, fmt.Errorf("identifier %q has a quote in the middle of a name", reference)
+			}
+			quoted = true
+			closingQuote = character
+
+		default:
+			identifier.WriteRune(character)
+		}
+	}
+
+	if quoted {
+		return nil, fmt.Errorf("identifier %q has an unterminated quote", reference)
+	}
+
+	if identifier.Len() == 0 {
+		return nil, fmt.Errorf("identifier %q has an empty name", reference)
+	}
+
+	return append(identifiers, identifier.String()), nil
+}
+
+func joinIdentifiers(identifiers ...string) string {
+	var quotedIdentifiers []string
+	for _, identifier := range identifiers {
+		if identifier == "" {
+			continue
+		}
+
+		quotedIdentifiers = append(quotedIdentifiers, quoteIdentifier(identifier))
+	}
+
+	return strings.Join(quotedIdentifiers, ".")
+}
+
+func quoteIdentifier(identifier string) string {
+	for _, character := range identifier {
+		isPlain := character == '_' ||
+			(character >= 'a' && character <= 'z') ||
+			(character >= 'A' && character <= 'Z') ||
+			(character >= '0' && character <= '9')
+		if !isPlain {
+			return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+		}
+	}
+
+	return identifier
+}
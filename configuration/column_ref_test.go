@@ -0,0 +1,98 @@
+package configuration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseColumnRefSplitsTableAndColumn(t *testing.T) {
+	columnRef, err := ParseColumnRef("customers.id")
+
+	assert.Nil(t, err)
+	assert.Equal(t, ColumnRef{Table: "customers", Column: "id"}, columnRef)
+	assert.Equal(t, "customers.id", columnRef.String())
+}
+
+func TestParseColumnRefSupportsSchemaQualifiedNames(t *testing.T) {
+	columnRef, err := ParseColumnRef("shop.customers.id")
+
+	assert.Nil(t, err)
+	assert.Equal(t, ColumnRef{Schema: "shop", Table: "customers", Column: "id"}, columnRef)
+	assert.Equal(t, TableRef{Schema: "shop", Table: "customers"}, columnRef.TableRef())
+}
+
+func TestParseColumnRefSupportsQuotedIdentifiers(t *testing.T) {
+	columnRef, err := ParseColumnRef(`"my.schema".` + "`order items`" + `."say ""hi"""`)
+
+	assert.Nil(t, err)
+	assert.Equal(t, ColumnRef{Schema: "my.schema", Table: "order items", Column: `say "hi"`}, columnRef)
+	assert.Equal(t, `"my.schema"."order items"."say ""hi"""`, columnRef.String())
+}
+
+func TestParseColumnRefRejectsMalformedReferences(t *testing.T) {
+	for _, reference := range []string{
+		"id",
+		"a.b.c.d",
+		"customers..id",
+		"customers.",
+		`customers."id`,
+		`customers."id"x`,
+		`customers.i"d"`,
+	} {
+		_, err := ParseColumnRef(reference)
+		assert.NotNil(t, err, reference)
+	}
+}
+
+func TestTableRefMatchesIgnoringMissingSchema(t *testing.T) {
+	customers := TableRef{Table: "customers"}
+	shopCustomers := TableRef{Schema: "shop", Table: "customers"}
+
+	assert.True(t, customers.Matches(shopCustomers))
+	assert.True(t, shopCustomers.Matches(shopCustomers))
+	assert.False(t, shopCustomers.Matches(TableRef{Schema: "archive", Table: "customers"}))
+	assert.False(t, customers.Matches(TableRef{Table: "products"}))
+}
+
+func TestValidateReportsColumnsOfAnotherTable(t *testing.T) {
+	ymlSchema := parseYmlSchema(t, `Name: Shop
+Resources:
+  Products:
+    TableName: shop.products
+    PrimaryKey:
+      - shop.products.id
+  Reviews:
+    TableName: reviews
+    PrimaryKey:
+      - reviews.id
+    Index:
+      Product:
+        - review.product_id
+    ForeignKeys:
+      - Type: NORMAL
+        Key: reviews.product_id
+        ResourceName: Reviews
+        ForeignKey: products.id
+      - Type: NORMAL
+        Key: product_id
+        ResourceName: Products
+        ForeignKey: archive.products.id
+`)
+
+	validationErrors := Validate(ymlSchema)
+
+	assert.Len(t, validationErrors, 4)
+	assert.Equal(t, "$.Resources.Reviews.Index.Product[0]", validationErrors[0].Path)
+	assert.Equal(t, "$.Resources.Reviews.ForeignKeys[0].ForeignKey", validationErrors[1].Path)
+	assert.Equal(t, 18, validationErrors[1].Line)
+	var columnTableMismatchError *ColumnTableMismatchError
+	assert.True(t, errors.As(validationErrors[1], &columnTableMismatchError))
+	assert.Equal(t, "Reviews", columnTableMismatchError.Resource)
+	assert.Equal(t, "products", columnTableMismatchError.Column.Table)
+	assert.Equal(t, "$.Resources.Reviews.ForeignKeys[1].Key", validationErrors[2].Path)
+	var invalidReferenceError *InvalidReferenceError
+	assert.True(t, errors.As(validationErrors[2], &invalidReferenceError))
+	assert.Equal(t, "$.Resources.Reviews.ForeignKeys[1].ForeignKey", validationErrors[3].Path)
+}
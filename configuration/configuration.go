@@ -1,69 +1,249 @@
 package configuration
 
+import "sort"
+
 type ForeignKey struct {
-	keyType         string
-	key             string
+	name            string
+	keyType         KeyType
+	delimitedKey    DelimitedKey
+	keys            []ColumnRef
 	foreignResource string
-	foreignKey      string
+	foreignKeys     []ColumnRef
 }
 
-func NewForeignKey(yamlForeignKey YmlForeignKey) *ForeignKey {
+func NewForeignKey(yamlForeignKey YmlForeignKey) (*ForeignKey, error) {
+	keys, err := parseColumnRefs(yamlForeignKey.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	foreignKeys, err := parseColumnRefs(yamlForeignKey.ForeignKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 || len(keys) != len(foreignKeys) {
+		return nil, &ForeignKeyArityError{Keys: len(keys), ForeignKeys: len(foreignKeys)}
+	}
+
+	keyType, delimitedKey, err := parseKeyType(yamlForeignKey)
+	if err != nil {
+		return nil, err
+	}
+
 	return &ForeignKey{
-		keyType:         yamlForeignKey.Type,
-		key:             yamlForeignKey.Key,
+		name:            foreignKeyName(yamlForeignKey),
+		keyType:         keyType,
+		delimitedKey:    delimitedKey,
+		keys:            keys,
 		foreignResource: yamlForeignKey.ResourceName,
-		foreignKey:      yamlForeignKey.ForeignKey,
+		foreignKeys:     foreignKeys,
+	}, nil
+}
+
+func foreignKeyName(ymlForeignKey YmlForeignKey) string {
+	if ymlForeignKey.Name != "" {
+		return ymlForeignKey.Name
 	}
+
+	return ymlForeignKey.ResourceName
+}
+
+func (foreignKey ForeignKey) Name() string {
+	return foreignKey.name
+}
+
+func (foreignKey ForeignKey) KeyType() KeyType {
+	return foreignKey.keyType
+}
+
+func (foreignKey ForeignKey) DelimitedKey() (DelimitedKey, bool) {
+	return foreignKey.delimitedKey, foreignKey.keyType == DelimitedKeyType
+}
+
+func (foreignKey ForeignKey) Keys() []ColumnRef {
+	return append([]ColumnRef(nil), foreignKey.keys...)
+}
+
+func (foreignKey ForeignKey) ForeignResource() string {
+	return foreignKey.foreignResource
+}
+
+func (foreignKey ForeignKey) ForeignKeys() []ColumnRef {
+	return append([]ColumnRef(nil), foreignKey.foreignKeys...)
 }
 
 type Resource struct {
+	name          string
 	tableName     string
-	primaryKey    []string
+	table         TableRef
+	primaryKey    []ColumnRef
 	autoIncrement bool
-	index         map[string][]string
+	index         map[string][]ColumnRef
 	foreignKeys   []ForeignKey
 }
 
-func NewResource(ymlResource YmlResource) *Resource {
+func NewResource(ymlResource YmlResource) (*Resource, error) {
+	table, err := ParseTableRef(ymlResource.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	primaryKey, err := parseColumnRefs(ymlResource.PrimaryKey)
+	if err != nil {
+		return nil, err
+	}
+
 	resource := &Resource{
 		tableName:     ymlResource.TableName,
-		primaryKey:    ymlResource.PrimaryKey,
+		table:         table,
+		primaryKey:    primaryKey,
 		autoIncrement: ymlResource.AutoIncrement,
-		index:         ymlResource.Index,
+		index:         make(map[string][]ColumnRef),
+	}
+
+	for indexName, columns := range ymlResource.Index {
+		resource.index[indexName], err = parseColumnRefs(columns)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	resource.foreignKeys = []ForeignKey{}
 	for _, ymlForeignKey := range ymlResource.ForeignKeys {
-		resource.foreignKeys = append(resource.foreignKeys, *NewForeignKey(ymlForeignKey))
+		foreignKey, err := NewForeignKey(ymlForeignKey)
+		if err != nil {
+			return nil, err
+		}
+
+		resource.foreignKeys = append(resource.foreignKeys, *foreignKey)
 	}
 
-	return resource
+	return resource, nil
+}
+
+func (resource Resource) Name() string {
+	return resource.name
+}
+
+func (resource Resource) TableName() string {
+	return resource.tableName
+}
+
+func (resource Resource) Table() TableRef {
+	return resource.table
+}
+
+func (resource Resource) PrimaryKey() []ColumnRef {
+	return append([]ColumnRef(nil), resource.primaryKey...)
+}
+
+func (resource Resource) AutoIncrement() bool {
+	return resource.autoIncrement
+}
+
+func (resource Resource) IndexNames() []string {
+	return sortedKeys(resource.index)
+}
+
+func (resource Resource) Index(indexName string) ([]ColumnRef, bool) {
+	columns, exists := resource.index[indexName]
+
+	return append([]ColumnRef(nil), columns...), exists
+}
+
+func (resource Resource) ForeignKeys() []ForeignKey {
+	return append([]ForeignKey(nil), resource.foreignKeys...)
 }
 
 type Relation struct {
-	fromTable string
-	fromKey   string
-	toTable   string
-	toKey     string
-	keyType   string
+	name         string
+	fromResource string
+	fromTable    string
+	fromKeys     []ColumnRef
+	toResource   string
+	toTable      string
+	toKeys       []ColumnRef
+	keyType      KeyType
+	delimitedKey DelimitedKey
 }
 
 func NewRelation(
+	name string,
+	fromResource string,
 	fromTable string,
-	fromKey string,
+	fromKeys []ColumnRef,
+	toResource string,
 	toTable string,
-	toKey string,
-	keyType string,
+	toKeys []ColumnRef,
+	keyType KeyType,
+	delimitedKey DelimitedKey,
 ) *Relation {
 	return &Relation{
-		fromTable: fromTable,
-		fromKey:   fromKey,
-		toTable:   toTable,
-		toKey:     toKey,
-		keyType:   keyType,
+		name:         name,
+		fromResource: fromResource,
+		fromTable:    fromTable,
+		fromKeys:     fromKeys,
+		toResource:   toResource,
+		toTable:      toTable,
+		toKeys:       toKeys,
+		keyType:      keyType,
+		delimitedKey: delimitedKey,
 	}
 }
 
+func relationKey(fromResource string, name string) string {
+	return fromResource + sharesSeparator + name
+}
+
+func (relation Relation) Name() string {
+	return relation.name
+}
+
+func (relation Relation) QualifiedName() string {
+	return relationKey(relation.fromResource, relation.name)
+}
+
+func (relation Relation) FromResource() string {
+	return relation.fromResource
+}
+
+func (relation Relation) FromTable() string {
+	return relation.fromTable
+}
+
+func (relation Relation) FromKeys() []ColumnRef {
+	return append([]ColumnRef(nil), relation.fromKeys...)
+}
+
+func (relation Relation) ToResource() string {
+	return relation.toResource
+}
+
+func (relation Relation) ToTable() string {
+	return relation.toTable
+}
+
+func (relation Relation) ToKeys() []ColumnRef {
+	return append([]ColumnRef(nil), relation.toKeys...)
+}
+
+func (relation Relation) KeyType() KeyType {
+	return relation.keyType
+}
+
+func (relation Relation) DelimitedKey() (DelimitedKey, bool) {
+	return relation.delimitedKey, relation.keyType == DelimitedKeyType
+}
+
+func (relation Relation) ExpandFromKey(value string) ([]string, error) {
+	if relation.keyType != DelimitedKeyType {
+		return []string{value}, nil
+	}
+
+	return relation.delimitedKey.Split(value)
+}
+
 type Relations map[string]Relation
 
 func NewRelations(relationMap map[string]Relation) *Relations {
@@ -71,6 +251,25 @@ func NewRelations(relationMap map[string]Relation) *Relations {
 	return &relations
 }
 
+func (relations Relations) Names() []string {
+	return sortedKeys(relations)
+}
+
+func (relations Relations) Relation(name string) (Relation, bool) {
+	relation, exists := relations[name]
+
+	return relation, exists
+}
+
+func (relations Relations) copy() Relations {
+	relationsCopy := make(Relations, len(relations))
+	for name, relation := range relations {
+		relationsCopy[name] = relation
+	}
+
+	return relationsCopy
+}
+
 type FromRelationship struct {
 	to Relations
 }
@@ -109,36 +308,244 @@ func NewRelationships(from map[string]FromRelationship, to map[string]ToRelation
 	return relationships
 }
 
+func (relationships Relationships) From(resourceName string) Relations {
+	return relationships.from[resourceName].to.copy()
+}
+
+func (relationships Relationships) To(resourceName string) Relations {
+	return relationships.to[resourceName].from.copy()
+}
+
+func (relationships Relationships) Between(resourceName string, otherResourceName string) []Relation {
+	var between []Relation
+	for _, relation := range relationships.from[resourceName].to {
+		if relation.toResource == otherResourceName {
+			between = append(between, relation)
+		}
+	}
+
+	if resourceName != otherResourceName {
+		for _, relation := range relationships.from[otherResourceName].to {
+			if relation.toResource == resourceName {
+				between = append(between, relation)
+			}
+		}
+	}
+
+	sort.Slice(between, func(i, j int) bool {
+		return between[i].QualifiedName() < between[j].QualifiedName()
+	})
+
+	return between
+}
+
+// DependencyOrder orders resources so that referenced resources come first.
+// Self references are left to row ordering; other cycles are reported as a
+// CyclicResourceDependencyError naming the resource path.
+func (relationships Relationships) DependencyOrder(resourceNames []string) ([]string, error) {
+	return relationships.dependencyOrder(resourceNames, func(cycle []string, relation Relation) error {
+		return &CyclicResourceDependencyError{Resources: cycle}
+	})
+}
+
+// DeferredDependencyOrder orders resources like DependencyOrder, but breaks
+// every cycle by returning the relation that closes it instead of failing.
+// Rows of the deferred relations have to be linked once all resources exist.
+func (relationships Relationships) DeferredDependencyOrder(resourceNames []string) ([]string, []Relation) {
+	var deferred []Relation
+	order, _ := relationships.dependencyOrder(resourceNames, func(cycle []string, relation Relation) error {
+		deferred = append(deferred, relation)
+
+		return nil
+	})
+
+	return order, deferred
+}
+
+func (relationships Relationships) dependencyOrder(
+	resourceNames []string,
+	onCycle func(cycle []string, relation Relation) error,
+) ([]string, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	included := make(map[string]bool, len(resourceNames))
+	for _, resourceName := range resourceNames {
+		included[resourceName] = true
+	}
+
+	states := make(map[string]int)
+	var stack []string
+	var order []string
+	var visit func(resourceName string) error
+	visit = func(resourceName string) error {
+		if states[resourceName] == visited {
+			return nil
+		}
+
+		states[resourceName] = visiting
+		stack = append(stack, resourceName)
+		relations := relationships.from[resourceName].to
+		for _, relationName := range relations.Names() {
+			relation := relations[relationName]
+			toResource := relation.toResource
+			if toResource == resourceName || !included[toResource] {
+				continue
+			}
+
+			if states[toResource] == visiting {
+				if err := onCycle(cyclePath(stack, toResource), relation); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := visit(toResource); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		states[resourceName] = visited
+		order = append(order, resourceName)
+
+		return nil
+	}
+
+	sortedResourceNames := append([]string(nil), resourceNames...)
+	sort.Strings(sortedResourceNames)
+	for _, resourceName := range sortedResourceNames {
+		if err := visit(resourceName); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+func cyclePath(stack []string, resourceName string) []string {
+	for index, stackedResourceName := range stack {
+		if stackedResourceName == resourceName {
+			return append(append([]string(nil), stack[index:]...), resourceName)
+		}
+	}
+
+	return nil
+}
+
 type SelectionCriteria interface {
 }
 
 type CustomSelectionCriteria struct {
 	criteria string
+	template CriteriaTemplate
 }
 
 func NewCustomSelectionCriteria() *CustomSelectionCriteria {
 	return &CustomSelectionCriteria{}
 }
 
+func (customSelectionCriteria CustomSelectionCriteria) Criteria() string {
+	return customSelectionCriteria.criteria
+}
+
+func (customSelectionCriteria CustomSelectionCriteria) Template() CriteriaTemplate {
+	return customSelectionCriteria.template
+}
+
+type RelationDirection string
+
+const (
+	ReferencingDirection RelationDirection = "Referencing"
+	ReferencedDirection  RelationDirection = "Referenced"
+)
+
+func isKnownRelationDirection(direction RelationDirection) bool {
+	switch direction {
+	case ReferencingDirection, ReferencedDirection:
+		return true
+	}
+
+	return false
+}
+
 type RelatedSelectionCriteria struct {
-	elements []Element
+	elements   []*Element
+	relations  []Relation
+	directions []RelationDirection
+	via        string
 }
 
 func NewRelatedSelectionCriteria() *RelatedSelectionCriteria {
 	return &RelatedSelectionCriteria{}
 }
 
+func (relatedSelectionCriteria RelatedSelectionCriteria) Elements() []*Element {
+	return append([]*Element(nil), relatedSelectionCriteria.elements...)
+}
+
+func (relatedSelectionCriteria RelatedSelectionCriteria) Relations() []Relation {
+	return append([]Relation(nil), relatedSelectionCriteria.relations...)
+}
+
+func (relatedSelectionCriteria RelatedSelectionCriteria) Directions() []RelationDirection {
+	return append([]RelationDirection(nil), relatedSelectionCriteria.directions...)
+}
+
+func (relatedSelectionCriteria RelatedSelectionCriteria) Via() string {
+	return relatedSelectionCriteria.via
+}
+
+type IndexMapping struct {
+	Upstream        *Element
+	Columns         []ColumnRef
+	UpstreamColumns []ColumnRef
+}
+
 type IndexedSelectionCriteria struct {
-	elements []Element
+	elements []*Element
+	index    string
+	columns  []ColumnRef
+	mappings []IndexMapping
 }
 
 func NewIndexedSelectionCriteria() *IndexedSelectionCriteria {
 	return &IndexedSelectionCriteria{}
 }
 
+func (indexedSelectionCriteria IndexedSelectionCriteria) Elements() []*Element {
+	return append([]*Element(nil), indexedSelectionCriteria.elements...)
+}
+
+func (indexedSelectionCriteria IndexedSelectionCriteria) Index() string {
+	return indexedSelectionCriteria.index
+}
+
+func (indexedSelectionCriteria IndexedSelectionCriteria) Columns() []ColumnRef {
+	return append([]ColumnRef(nil), indexedSelectionCriteria.columns...)
+}
+
+func (indexedSelectionCriteria IndexedSelectionCriteria) Mappings() []IndexMapping {
+	mappings := make([]IndexMapping, 0, len(indexedSelectionCriteria.mappings))
+	for _, mapping := range indexedSelectionCriteria.mappings {
+		mappings = append(mappings, IndexMapping{
+			Upstream:        mapping.Upstream,
+			Columns:         append([]ColumnRef(nil), mapping.Columns...),
+			UpstreamColumns: append([]ColumnRef(nil), mapping.UpstreamColumns...),
+		})
+	}
+
+	return mappings
+}
+
 type Element struct {
+	path              ElementPath
 	resource          Resource
 	selectionCriteria SelectionCriteria
+	shares            *ElementPath
+	recursion         *Recursion
 }
 
 func NewElement(resource Resource) *Element {
@@ -147,9 +554,42 @@ func NewElement(resource Resource) *Element {
 	}
 }
 
+func (element Element) Name() string {
+	return element.path.Element
+}
+
+func (element Element) Path() ElementPath {
+	return element.path
+}
+
+func (element Element) Resource() Resource {
+	return element.resource
+}
+
+func (element Element) SelectionCriteria() SelectionCriteria {
+	return element.selectionCriteria
+}
+
+func (element Element) Shares() (ElementPath, bool) {
+	if element.shares == nil {
+		return ElementPath{}, false
+	}
+
+	return *element.shares, true
+}
+
+func (element Element) Recursion() (Recursion, bool) {
+	if element.recursion == nil {
+		return Recursion{}, false
+	}
+
+	return *element.recursion, true
+}
+
 type Component struct {
+	name        string
 	description string
-	elements    map[string]Element
+	elements    map[string]*Element
 }
 
 func NewComponent(description string) *Component {
@@ -159,8 +599,37 @@ func NewComponent(description string) *Component {
 	return component
 }
 
+func (component Component) Name() string {
+	return component.name
+}
+
+func (component Component) Description() string {
+	return component.description
+}
+
+func (component Component) ElementNames() []string {
+	return sortedKeys(component.elements)
+}
+
+func (component Component) Element(elementName string) (*Element, bool) {
+	element, exists := component.elements[elementName]
+
+	return element, exists
+}
+
+func (component Component) Elements() []*Element {
+	elements := make([]*Element, 0, len(component.elements))
+	for _, elementName := range component.ElementNames() {
+		elements = append(elements, component.elements[elementName])
+	}
+
+	return elements
+}
+
 type Entity struct {
+	name        string
 	description string
+	parameters  Parameters
 	components  map[string]Component
 }
 
@@ -171,12 +640,53 @@ func NewEntity(description string) *Entity {
 	return entity
 }
 
+func (entity Entity) Name() string {
+	return entity.name
+}
+
+func (entity Entity) Description() string {
+	return entity.description
+}
+
+func (entity Entity) Parameters() Parameters {
+	parameters := make(Parameters, len(entity.parameters))
+	for name, parameter := range entity.parameters {
+		parameters[name] = parameter
+	}
+
+	return parameters
+}
+
+func (entity Entity) BindParameters(values map[string]any) (map[string]any, error) {
+	return entity.parameters.Bind(values)
+}
+
+func (entity Entity) ComponentNames() []string {
+	return sortedKeys(entity.components)
+}
+
+func (entity Entity) Component(componentName string) (Component, bool) {
+	component, exists := entity.components[componentName]
+
+	return component, exists
+}
+
+func (entity Entity) Components() []Component {
+	components := make([]Component, 0, len(entity.components))
+	for _, componentName := range entity.ComponentNames() {
+		components = append(components, entity.components[componentName])
+	}
+
+	return components
+}
+
 type Configuration struct {
-	name          string
-	description   string
-	resources     map[string]Resource
-	relationships Relationships
-	entities      map[string]Entity
+	name            string
+	description     string
+	resources       map[string]Resource
+	relationships   Relationships
+	entities        map[string]Entity
+	dependencyGraph DependencyGraph
 }
 
 func NewConfiguration() *Configuration {
@@ -185,3 +695,63 @@ func NewConfiguration() *Configuration {
 
 	return configuration
 }
+
+func (configuration Configuration) Name() string {
+	return configuration.name
+}
+
+func (configuration Configuration) Description() string {
+	return configuration.description
+}
+
+func (configuration Configuration) ResourceNames() []string {
+	return sortedKeys(configuration.resources)
+}
+
+func (configuration Configuration) Resource(resourceName string) (Resource, bool) {
+	resource, exists := configuration.resources[resourceName]
+
+	return resource, exists
+}
+
+func (configuration Configuration) Resources() []Resource {
+	resources := make([]Resource, 0, len(configuration.resources))
+	for _, resourceName := range configuration.ResourceNames() {
+		resources = append(resources, configuration.resources[resourceName])
+	}
+
+	return resources
+}
+
+func (configuration Configuration) Relationships() Relationships {
+	return configuration.relationships
+}
+
+func (configuration Configuration) EntityNames() []string {
+	return sortedKeys(configuration.entities)
+}
+
+func (configuration Configuration) Entity(entityName string) (Entity, bool) {
+	entity, exists := configuration.entities[entityName]
+
+	return entity, exists
+}
+
+func (configuration Configuration) Entities() []Entity {
+	entities := make([]Entity, 0, len(configuration.entities))
+	for _, entityName := range configuration.EntityNames() {
+		entities = append(entities, configuration.entities[entityName])
+	}
+
+	return entities
+}
+
+func (configuration Configuration) Element(elementPath ElementPath) (*Element, bool) {
+	element, exists := configuration.entities[elementPath.Entity].components[elementPath.Component].elements[elementPath.Element]
+
+	return element, exists
+}
+
+func (configuration Configuration) DependencyGraph() DependencyGraph {
+	return configuration.dependencyGraph
+}
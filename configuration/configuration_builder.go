@@ -1,22 +1,16 @@
 package configuration
 
 import (
-	"os"
+	"fmt"
 )
 
-var Path string
-
-func init() {
-	dir, _ := os.Getwd()
-	Path = dir + "/configurations"
-}
-
 type Builder interface {
-	Build(ymlSchema YmlSchema) []byte
+	Build(ymlSchema YmlSchema) (*Configuration, error)
 }
 
 type BuilderYml struct {
 	configuration *Configuration
+	ymlEntities   map[string]YmlEntity
 }
 
 func NewConfigurationBuilderYml() *BuilderYml {
@@ -59,31 +53,46 @@ func (configurationBuilder *BuilderYml) get() *Configuration {
 	return configurationBuilder.configuration
 }
 
-func (configurationBuilder *BuilderYml) Build(ymlSchema YmlSchema) *Configuration {
+func (configurationBuilder *BuilderYml) Build(ymlSchema YmlSchema) (*Configuration, error) {
+	if validationErrors := Validate(ymlSchema); len(validationErrors) > 0 {
+		return nil, fmt.Errorf("invalid configuration %q: %w", ymlSchema.Name, ValidationErrors(validationErrors))
+	}
+
 	configuration := configurationBuilder.
 		setName(ymlSchema.Name).
 		setDescription(ymlSchema.Description)
 
-	resources := configurationBuilder.buildResources(ymlSchema.Resources)
+	resources, err := configurationBuilder.buildResources(ymlSchema.Resources)
+	if err != nil {
+		return nil, fmt.Errorf("building configuration %q: %w", ymlSchema.Name, err)
+	}
 	configuration.setResources(resources)
 
 	relationships := configurationBuilder.buildRelationships(resources)
 	configuration.setRelationships(relationships)
 
-	entities := configurationBuilder.buildEntities(ymlSchema.Entities)
+	entities, err := configurationBuilder.buildEntities(ymlSchema.Entities)
+	if err != nil {
+		return nil, fmt.Errorf("building configuration %q: %w", ymlSchema.Name, err)
+	}
 	configuration.setEntities(entities)
 
-	return configuration.get()
+	return configuration.get(), nil
 }
 
-func (configurationBuilder *BuilderYml) buildResources(ymlResources map[string]YmlResource) map[string]Resource {
+func (configurationBuilder *BuilderYml) buildResources(ymlResources map[string]YmlResource) (map[string]Resource, error) {
 	resources := make(map[string]Resource)
-	for resourceName, ymlResource := range ymlResources {
-		resource := NewResource(ymlResource)
+	for _, resourceName := range sortedKeys(ymlResources) {
+		resource, err := NewResource(ymlResources[resourceName])
+		if err != nil {
+			return nil, fmt.Errorf("building resource %q: %w", resourceName, err)
+		}
+
+		resource.name = resourceName
 		resources[resourceName] = *resource
 	}
 
-	return resources
+	return resources, nil
 }
 
 func (configurationBuilder *BuilderYml) buildRelationships(resources map[string]Resource) Relationships {
@@ -91,13 +100,7 @@ func (configurationBuilder *BuilderYml) buildRelationships(resources map[string]
 	for resourceName, resource := range resources {
 		relations := make(map[string]Relation)
 		for _, foreignKey := range resource.foreignKeys {
-			relations[foreignKey.foreignResource] = *NewRelation(
-				resources[resourceName].tableName,
-				foreignKey.key,
-				resources[foreignKey.foreignResource].tableName,
-				foreignKey.foreignKey,
-				foreignKey.keyType,
-			)
+			relations[foreignKey.name] = newForeignKeyRelation(resources, resourceName, foreignKey)
 		}
 
 		toRelations := *NewRelations(relations)
@@ -111,13 +114,8 @@ func (configurationBuilder *BuilderYml) buildRelationships(resources map[string]
 			if _, exists := relations[foreignKey.foreignResource]; !exists {
 				relations[foreignKey.foreignResource] = make(map[string]Relation)
 			}
-			relations[foreignKey.foreignResource][resourceName] = *NewRelation(
-				resources[resourceName].tableName,
-				foreignKey.key,
-				resources[foreignKey.foreignResource].tableName,
-				foreignKey.foreignKey,
-				foreignKey.keyType,
-			)
+			relation := newForeignKeyRelation(resources, resourceName, foreignKey)
+			relations[foreignKey.foreignResource][relation.QualifiedName()] = relation
 		}
 	}
 	for resourceName, relations := range relations {
@@ -128,83 +126,170 @@ func (configurationBuilder *BuilderYml) buildRelationships(resources map[string]
 	return *NewRelationships(fromRelationshipMap, toRelationshipMap)
 }
 
-func (configurationBuilder *BuilderYml) buildEntities(ymlEntities map[string]YmlEntity) map[string]Entity {
+func newForeignKeyRelation(resources map[string]Resource, resourceName string, foreignKey ForeignKey) Relation {
+	return *NewRelation(
+		foreignKey.name,
+		resourceName,
+		resources[resourceName].tableName,
+		foreignKey.keys,
+		foreignKey.foreignResource,
+		resources[foreignKey.foreignResource].tableName,
+		foreignKey.foreignKeys,
+		foreignKey.keyType,
+		foreignKey.delimitedKey,
+	)
+}
+
+func (configurationBuilder *BuilderYml) buildEntities(ymlEntities map[string]YmlEntity) (map[string]Entity, error) {
+	configurationBuilder.ymlEntities = ymlEntities
+
+	dependencyGraph, err := NewDependencyGraph(ymlEntities)
+	if err != nil {
+		return nil, err
+	}
+	configurationBuilder.configuration.dependencyGraph = *dependencyGraph
+
+	elements := make(map[ElementPath]*Element)
+	for _, elementPath := range dependencyGraph.Order() {
+		element, err := configurationBuilder.buildElement(elementPath, elements)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"building entity %q: building component %q: building element %q: %w",
+				elementPath.Entity,
+				elementPath.Component,
+				elementPath.Element,
+				err,
+			)
+		}
+
+		elements[elementPath] = element
+	}
+
 	entities := make(map[string]Entity)
-	for entityName, ymlEntity := range ymlEntities {
+	for _, entityName := range sortedKeys(ymlEntities) {
+		ymlEntity := ymlEntities[entityName]
 		entity := *NewEntity(ymlEntity.Description)
-		if entity.components == nil {
-			entity.components = make(map[string]Component)
+		entity.name = entityName
+		entity.parameters = make(Parameters)
+		for _, parameterName := range sortedKeys(ymlEntity.Parameters) {
+			parameter, err := NewParameter(parameterName, ymlEntity.Parameters[parameterName])
+			if err != nil {
+				return nil, fmt.Errorf("building entity %q: %w", entityName, err)
+			}
+
+			entity.parameters[parameterName] = *parameter
+		}
+		entity.components = make(map[string]Component)
+		for _, componentName := range sortedKeys(ymlEntity.Components) {
+			ymlComponent := ymlEntity.Components[componentName]
+			component := *NewComponent(ymlComponent.Description)
+			component.name = componentName
+			component.elements = make(map[string]*Element)
+			for elementName := range ymlComponent.Elements {
+				component.elements[elementName] = elements[*NewElementPath(entityName, componentName, elementName)]
+			}
+
+			entity.components[componentName] = component
 		}
-		entity.components = configurationBuilder.buildComponents(ymlEntity.Components)
+
 		entities[entityName] = entity
 	}
 
-	return entities
+	return entities, nil
 }
 
-func (configurationBuilder *BuilderYml) buildComponents(ymlComponents map[string]YmlComponent) map[string]Component {
-	components := make(map[string]Component)
-	for componentName, ymlComponent := range ymlComponents {
-		component := *NewComponent(ymlComponent.Description)
-		if component.elements == nil {
-			component.elements = make(map[string]Element)
+func (configurationBuilder *BuilderYml) buildElement(
+	elementPath ElementPath,
+	elements map[ElementPath]*Element,
+) (*Element, error) {
+	ymlElement, _ := lookupYmlElement(configurationBuilder.ymlEntities, elementPath)
+
+	resourceName := ymlElement.Resource
+	var sharedElementPath *ElementPath
+	if ymlElement.Shares != "" {
+		resolvedElementPath, resolvedYmlElement, err := resolveShares(configurationBuilder.ymlEntities, elementPath)
+		if err != nil {
+			return nil, err
 		}
 
-		component.elements = configurationBuilder.buildElements(ymlComponent.Elements)
-		components[componentName] = component
+		resourceName = resolvedYmlElement.Resource
+		sharedElementPath = &resolvedElementPath
 	}
 
-	return components
-}
+	resource, exists := configurationBuilder.configuration.resources[resourceName]
+	if !exists {
+		return nil, &UnknownResourceError{Resource: resourceName}
+	}
 
-func (configurationBuilder *BuilderYml) buildElements(ymlElements map[string]YmlElement) map[string]Element {
-	elements := make(map[string]Element)
+	element := NewElement(resource)
+	element.path = elementPath
+	element.shares = sharedElementPath
 
-	for elementName, ymlElement := range ymlElements {
-		element := *NewElement(configurationBuilder.configuration.resources[ymlElement.Resource])
+	ymlSelectionCriteria := ymlElement.SelectionCriteria
+	if ymlSelectionCriteria.Type != "" {
+		factory, exists := lookupSelectionCriteriaFactory(ymlSelectionCriteria.Type)
+		if !exists {
+			return nil, &UnknownSelectionCriteriaTypeError{Type: ymlSelectionCriteria.Type}
+		}
 
-		if element.selectionCriteria == "Related" {
-			relatedSelectionCriteria := NewRelatedSelectionCriteria()
-			element.selectionCriteria = relatedSelectionCriteria
+		relatedElements, err := relatedElements(elements, elementPath, ymlSelectionCriteria.Elements)
+		if err != nil {
+			return nil, err
 		}
 
-		elements[elementName] = element
-	}
+		selectionCriteria, err := factory(SelectionCriteriaDefinition{
+			Type:          ymlSelectionCriteria.Type,
+			Criteria:      ymlSelectionCriteria.Criteria,
+			Index:         ymlSelectionCriteria.Index,
+			Via:           ymlSelectionCriteria.Via,
+			Direction:     ymlSelectionCriteria.Direction,
+			Options:       ymlSelectionCriteria.Options,
+			Element:       element,
+			Elements:      relatedElements,
+			Relationships: configurationBuilder.configuration.relationships,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("building %s selection criteria: %w", ymlSelectionCriteria.Type, err)
+		}
 
-	for elementName, ymlElement := range ymlElements {
-		ymlSelectionCriteria := ymlElement.SelectionCriteria
-		element := elements[elementName]
-		switch ymlSelectionCriteria.Type {
+		element.selectionCriteria = selectionCriteria
+	}
 
-		case "Custom":
-			customSelectionCriteria := NewCustomSelectionCriteria()
-			customSelectionCriteria.criteria = ymlSelectionCriteria.Criteria
-			element.selectionCriteria = customSelectionCriteria
+	if ymlSelectionCriteria.Recursive != nil {
+		recursion, err := configurationBuilder.buildRecursion(resourceName, *ymlSelectionCriteria.Recursive)
+		if err != nil {
+			return nil, err
+		}
 
-		case "Index":
-			indexedSelectionCriteria := NewIndexedSelectionCriteria()
-			var relatedElements []Element
-			for _, relatedElementName := range ymlSelectionCriteria.Elements {
-				relatedElements = append(relatedElements, elements[relatedElementName])
-			}
+		element.recursion = recursion
+	}
 
-			indexedSelectionCriteria.elements = relatedElements
-			element.selectionCriteria = indexedSelectionCriteria
+	return element, nil
+}
 
-		case "Related":
-			relatedSelectionCriteria := NewRelatedSelectionCriteria()
-			var relatedElements []Element
-			for _, relatedElementName := range ymlSelectionCriteria.Elements {
-				relatedElements = append(relatedElements, elements[relatedElementName])
-			}
+func (configurationBuilder *BuilderYml) buildRecursion(resourceName string, ymlRecursion YmlRecursion) (*Recursion, error) {
+	relation, err := relationVia(configurationBuilder.configuration.relationships, resourceName, resourceName, ymlRecursion.Via)
+	if err != nil {
+		return nil, err
+	}
 
-			relatedSelectionCriteria.elements = relatedElements
-			element.selectionCriteria = relatedSelectionCriteria
+	return NewRecursion(RecursionDirection(ymlRecursion.Direction), relation, ymlRecursion.MaxDepth), nil
+}
 
+func relatedElements(
+	elements map[ElementPath]*Element,
+	elementPath ElementPath,
+	relatedElementNames []string,
+) ([]*Element, error) {
+	var relatedElements []*Element
+	for _, relatedElementName := range relatedElementNames {
+		relatedElement, exists := elements[*NewElementPath(elementPath.Entity, elementPath.Component, relatedElementName)]
+		if !exists {
+			return nil, &UnknownElementError{Element: relatedElementName}
 		}
 
-		elements[elementName] = element
+		relatedElements = append(relatedElements, relatedElement)
 	}
 
-	return elements
+	return relatedElements, nil
 }
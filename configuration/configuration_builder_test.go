@@ -1,28 +1,35 @@
 package configuration
 
 import (
-	"os"
+	"embed"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
+//go:embed configurations
+var testConfigurations embed.FS
+
 func getYmlSchema() YmlSchema {
-	ymlContent, _ := os.ReadFile(Path + "/entities_test.yml")
+	ymlContent, _ := testConfigurations.ReadFile("configurations/entities_test.yml")
 	ymlSchema, _ := NewYmlParser().Parse(string(ymlContent))
 	return ymlSchema
 }
 
 func TestConfigurationBuilderCanBuildConfigurationFromFile(t *testing.T) {
 	configurationBuilder := NewConfigurationBuilderYml()
-	configuration := configurationBuilder.Build(getYmlSchema())
+	configuration, err := configurationBuilder.Build(getYmlSchema())
+	assert.Nil(t, err)
 
 	assert.IsType(t, &Configuration{}, configuration)
 }
 
 func TestConfigurationBuilderCanBuildConfigurationWithResources(t *testing.T) {
 	configurationBuilder := NewConfigurationBuilderYml()
-	configuration := configurationBuilder.Build(getYmlSchema())
+	configuration, err := configurationBuilder.Build(getYmlSchema())
+	assert.Nil(t, err)
 
 	assert.IsType(t, &Configuration{}, configuration)
 	resources := configuration.resources
@@ -36,7 +43,8 @@ func TestConfigurationBuilderCanBuildConfigurationWithResources(t *testing.T) {
 
 func TestConfigurationBuilderCanExtractRelationshipsFromGivenTable(t *testing.T) {
 	configurationBuilder := NewConfigurationBuilderYml()
-	configuration := configurationBuilder.Build(getYmlSchema())
+	configuration, err := configurationBuilder.Build(getYmlSchema())
+	assert.Nil(t, err)
 
 	assert.IsType(t, Relationships{}, configuration.relationships)
 
@@ -46,33 +54,35 @@ func TestConfigurationBuilderCanExtractRelationshipsFromGivenTable(t *testing.T)
 	assert.Contains(t, configuration.relationships.from["MyTestResource"].to, "MyTestResource2")
 	assert.IsType(t, Relation{}, configuration.relationships.from["MyTestResource"].to["MyTestResource2"])
 	assert.Equal(t, "my_test_table", configuration.relationships.from["MyTestResource"].to["MyTestResource2"].fromTable)
-	assert.Equal(t, "my_test_table.fk1", configuration.relationships.from["MyTestResource"].to["MyTestResource2"].fromKey)
+	assert.Equal(t, []ColumnRef{{Table: "my_test_table", Column: "fk1"}}, configuration.relationships.from["MyTestResource"].to["MyTestResource2"].fromKeys)
 	assert.Equal(t, "my_test_table2", configuration.relationships.from["MyTestResource"].to["MyTestResource2"].toTable)
-	assert.Equal(t, "my_test_table2.id", configuration.relationships.from["MyTestResource"].to["MyTestResource2"].toKey)
-	assert.Equal(t, "NORMAL", configuration.relationships.from["MyTestResource"].to["MyTestResource2"].keyType)
+	assert.Equal(t, []ColumnRef{{Table: "my_test_table2", Column: "id"}}, configuration.relationships.from["MyTestResource"].to["MyTestResource2"].toKeys)
+	assert.Equal(t, NormalKeyType, configuration.relationships.from["MyTestResource"].to["MyTestResource2"].keyType)
 }
 
 func TestConfigurationBuilderCanExtractRelationshipsToGivenTable(t *testing.T) {
 
 	configurationBuilder := NewConfigurationBuilderYml()
-	configuration := configurationBuilder.Build(getYmlSchema())
+	configuration, err := configurationBuilder.Build(getYmlSchema())
+	assert.Nil(t, err)
 
 	assert.IsType(t, Relationships{}, configuration.relationships)
 
 	assert.Contains(t, configuration.relationships.to, "MyTestResource2")
 	assert.IsType(t, ToRelationship{}, configuration.relationships.to["MyTestResource2"])
 	assert.IsType(t, Relations{}, configuration.relationships.to["MyTestResource2"].from)
-	assert.Contains(t, configuration.relationships.to["MyTestResource2"].from, "MyTestResource")
-	assert.Equal(t, "my_test_table", configuration.relationships.to["MyTestResource2"].from["MyTestResource"].fromTable)
-	assert.Equal(t, "my_test_table.fk1", configuration.relationships.to["MyTestResource2"].from["MyTestResource"].fromKey)
-	assert.Equal(t, "my_test_table2", configuration.relationships.to["MyTestResource2"].from["MyTestResource"].toTable)
-	assert.Equal(t, "my_test_table2.id", configuration.relationships.to["MyTestResource2"].from["MyTestResource"].toKey)
-	assert.Equal(t, "NORMAL", configuration.relationships.to["MyTestResource2"].from["MyTestResource"].keyType)
+	assert.Contains(t, configuration.relationships.to["MyTestResource2"].from, "MyTestResource::MyTestResource2")
+	assert.Equal(t, "my_test_table", configuration.relationships.to["MyTestResource2"].from["MyTestResource::MyTestResource2"].fromTable)
+	assert.Equal(t, []ColumnRef{{Table: "my_test_table", Column: "fk1"}}, configuration.relationships.to["MyTestResource2"].from["MyTestResource::MyTestResource2"].fromKeys)
+	assert.Equal(t, "my_test_table2", configuration.relationships.to["MyTestResource2"].from["MyTestResource::MyTestResource2"].toTable)
+	assert.Equal(t, []ColumnRef{{Table: "my_test_table2", Column: "id"}}, configuration.relationships.to["MyTestResource2"].from["MyTestResource::MyTestResource2"].toKeys)
+	assert.Equal(t, NormalKeyType, configuration.relationships.to["MyTestResource2"].from["MyTestResource::MyTestResource2"].keyType)
 }
 
 func TestConfigurationBuilderCanBuildConfigurationWithEntities(t *testing.T) {
 	configurationBuilder := NewConfigurationBuilderYml()
-	configuration := configurationBuilder.Build(getYmlSchema())
+	configuration, err := configurationBuilder.Build(getYmlSchema())
+	assert.Nil(t, err)
 
 	assert.IsType(t, map[string]Entity{}, configuration.entities)
 	subjectEntity := configuration.entities["MyTestEntity"]
@@ -80,7 +90,7 @@ func TestConfigurationBuilderCanBuildConfigurationWithEntities(t *testing.T) {
 	assert.IsType(t, map[string]Component{}, subjectEntity.components)
 	subjectComponent := subjectEntity.components["MyTestComponent1"]
 	assert.Equal(t, "This is a test component", subjectComponent.description)
-	assert.IsType(t, map[string]Element{}, subjectComponent.elements)
+	assert.IsType(t, map[string]*Element{}, subjectComponent.elements)
 	subjectElement := subjectComponent.elements["ElementA"]
 	assert.IsType(t, Resource{}, subjectElement.resource)
 	assert.Equal(t, "my_test_table", subjectElement.resource.tableName)
@@ -88,7 +98,8 @@ func TestConfigurationBuilderCanBuildConfigurationWithEntities(t *testing.T) {
 
 func TestEntityFromConfigurationBuiltUsingBuilderContainsPhasesAndTasks(t *testing.T) {
 	configurationBuilder := NewConfigurationBuilderYml()
-	configuration := configurationBuilder.Build(getYmlSchema())
+	configuration, err := configurationBuilder.Build(getYmlSchema())
+	assert.Nil(t, err)
 
 	assert.IsType(t, map[string]Entity{}, configuration.entities)
 	subjectEntity := configuration.entities["MyTestEntity"]
@@ -96,7 +107,7 @@ func TestEntityFromConfigurationBuiltUsingBuilderContainsPhasesAndTasks(t *testi
 	assert.IsType(t, map[string]Component{}, subjectEntity.components)
 	subjectPhase := subjectEntity.components["MyTestComponent1"]
 	assert.Equal(t, "This is a test component", subjectPhase.description)
-	assert.IsType(t, map[string]Element{}, subjectPhase.elements)
+	assert.IsType(t, map[string]*Element{}, subjectPhase.elements)
 	subjectElement := subjectPhase.elements["ElementA"]
 
 	assert.IsType(t, Resource{}, subjectElement.resource)
@@ -105,7 +116,8 @@ func TestEntityFromConfigurationBuiltUsingBuilderContainsPhasesAndTasks(t *testi
 
 func TestTasksInConfigurationBuiltUsingBuilderContainsResourceAndSelectionCriteria(t *testing.T) {
 	configurationBuilder := NewConfigurationBuilderYml()
-	configuration := configurationBuilder.Build(getYmlSchema())
+	configuration, err := configurationBuilder.Build(getYmlSchema())
+	assert.Nil(t, err)
 
 	assert.IsType(t, map[string]Entity{}, configuration.entities)
 	subjectEntity := configuration.entities["MyTestEntity"]
@@ -130,3 +142,206 @@ func TestTasksInConfigurationBuiltUsingBuilderContainsResourceAndSelectionCriter
 	assert.NotNil(t, elementD.selectionCriteria)
 	assert.IsType(t, &RelatedSelectionCriteria{}, elementD.selectionCriteria)
 }
+
+func TestConfigurationBuilderReportsCyclicElementDependencies(t *testing.T) {
+	ymlSchema, _ := NewYmlParser().Parse(`Name: Example
+Resources:
+  MyTestResource:
+    TableName: my_test_table
+    ForeignKeys:
+      - Type: NORMAL
+        Key: my_test_table.parent_id
+        ResourceName: MyTestResource
+        ForeignKey: my_test_table.id
+Entities:
+  MyTestEntity:
+    Components:
+      MyTestComponent1:
+        Elements:
+          ElementA:
+            Resource: MyTestResource
+            SelectionCriteria:
+              Type: Related
+              Elements:
+                - ElementC
+          ElementB:
+            Resource: MyTestResource
+            SelectionCriteria:
+              Type: Related
+              Elements:
+                - ElementA
+          ElementC:
+            Resource: MyTestResource
+            SelectionCriteria:
+              Type: Related
+              Elements:
+                - ElementB
+`)
+
+	configuration, err := NewConfigurationBuilderYml().Build(ymlSchema)
+
+	assert.Nil(t, configuration)
+	var cyclicElementDependencyError *CyclicElementDependencyError
+	assert.True(t, errors.As(err, &cyclicElementDependencyError))
+	assert.Equal(
+		t,
+		[]string{
+			"MyTestEntity::MyTestComponent1::ElementA",
+			"MyTestEntity::MyTestComponent1::ElementC",
+			"MyTestEntity::MyTestComponent1::ElementB",
+			"MyTestEntity::MyTestComponent1::ElementA",
+		},
+		cyclicElementDependencyError.Elements,
+	)
+	assert.Contains(t, err.Error(), `building configuration "Example"`)
+	assert.Contains(t, err.Error(), "MyTestEntity::MyTestComponent1::ElementA -> MyTestEntity::MyTestComponent1::ElementC")
+}
+
+const compositeForeignKeyYmlConfiguration = `Name: Tenants
+Resources:
+  Customers:
+    TableName: customers
+    PrimaryKey:
+      - customers.tenant_id
+      - customers.id
+  Orders:
+    TableName: orders
+    PrimaryKey:
+      - orders.tenant_id
+      - orders.id
+    ForeignKeys:
+      - Type: NORMAL
+        Key:
+          - orders.tenant_id
+          - orders.customer_id
+        ResourceName: Customers
+        ForeignKey:
+          - customers.tenant_id
+          - customers.id
+`
+
+func TestYmlParserAcceptsSingleAndCompositeForeignKeys(t *testing.T) {
+	ymlSchema, err := NewYmlParser().Parse(compositeForeignKeyYmlConfiguration + `  Payments:
+    TableName: payments
+    ForeignKeys:
+      - Type: NORMAL
+        Key: payments.order_id
+        ResourceName: Orders
+        ForeignKey: orders.id
+`)
+
+	assert.Nil(t, err)
+	assert.Equal(t, YmlColumns{"orders.tenant_id", "orders.customer_id"}, ymlSchema.Resources["Orders"].ForeignKeys[0].Key)
+	assert.Equal(t, YmlColumns{"customers.tenant_id", "customers.id"}, ymlSchema.Resources["Orders"].ForeignKeys[0].ForeignKey)
+	assert.Equal(t, YmlColumns{"payments.order_id"}, ymlSchema.Resources["Payments"].ForeignKeys[0].Key)
+	assert.Equal(t, YmlColumns{"orders.id"}, ymlSchema.Resources["Payments"].ForeignKeys[0].ForeignKey)
+}
+
+func TestConfigurationBuilderCarriesCompositeForeignKeysIntoRelations(t *testing.T) {
+	ymlSchema, _ := NewYmlParser().Parse(compositeForeignKeyYmlConfiguration)
+
+	configuration, err := NewConfigurationBuilderYml().Build(ymlSchema)
+
+	assert.Nil(t, err)
+	relation := configuration.relationships.from["Orders"].to["Customers"]
+	assert.Equal(t, []ColumnRef{{Table: "orders", Column: "tenant_id"}, {Table: "orders", Column: "customer_id"}}, relation.fromKeys)
+	assert.Equal(t, []ColumnRef{{Table: "customers", Column: "tenant_id"}, {Table: "customers", Column: "id"}}, relation.toKeys)
+	assert.Equal(t, relation, configuration.relationships.to["Customers"].from["Orders::Customers"])
+}
+
+func TestValidateReportsForeignKeysWithMismatchedColumnCounts(t *testing.T) {
+	ymlSchema, _ := NewYmlParser().Parse(compositeForeignKeyYmlConfiguration + `  Payments:
+    TableName: payments
+    ForeignKeys:
+      - Type: NORMAL
+        Key: payments.order_id
+        ResourceName: Orders
+        ForeignKey:
+          - orders.tenant_id
+          - orders.id
+`)
+
+	validationErrors := Validate(ymlSchema)
+
+	assert.Len(t, validationErrors, 1)
+	assert.Equal(t, "$.Resources.Payments.ForeignKeys[0]", validationErrors[0].Path)
+	var foreignKeyArityError *ForeignKeyArityError
+	assert.True(t, errors.As(validationErrors[0], &foreignKeyArityError))
+	assert.Equal(t, 1, foreignKeyArityError.Keys)
+	assert.Equal(t, 2, foreignKeyArityError.ForeignKeys)
+}
+
+func TestConfigurationBuilderStoresTheDirectionOfRelatedSelectionCriteria(t *testing.T) {
+	const ymlConfiguration = `Name: Example
+Resources:
+  Customers:
+    TableName: customers
+  Categories:
+    TableName: categories
+    ForeignKeys:
+      - Type: NORMAL
+        Name: Parent
+        Key: categories.parent_id
+        ResourceName: Categories
+        ForeignKey: categories.id
+      - Type: NORMAL
+        Key: categories.owner_id
+        ResourceName: Customers
+        ForeignKey: customers.id
+Entities:
+  Catalogue:
+    Components:
+      Categories:
+        Elements:
+          Roots:
+            Resource: Categories
+          Children:
+            Resource: Categories
+            SelectionCriteria:
+              Type: Related
+              Via: Parent
+              Elements:
+                - Roots
+          Parents:
+            Resource: Categories
+            SelectionCriteria:
+              Type: Related
+              Via: Parent
+              Direction: Referenced
+              Elements:
+                - Roots
+          Owners:
+            Resource: Customers
+            SelectionCriteria:
+              Type: Related
+              Elements:
+                - Roots
+`
+	ymlSchema, err := NewYmlParser().Parse(ymlConfiguration)
+	assert.Nil(t, err)
+	configuration, err := NewConfigurationBuilderYml().Build(ymlSchema)
+	assert.Nil(t, err)
+
+	elements := configuration.entities["Catalogue"].components["Categories"].elements
+	assert.Equal(t, []RelationDirection{ReferencingDirection}, elements["Children"].selectionCriteria.(*RelatedSelectionCriteria).Directions())
+	assert.Equal(t, []RelationDirection{ReferencedDirection}, elements["Parents"].selectionCriteria.(*RelatedSelectionCriteria).Directions())
+	assert.Equal(t, []RelationDirection{ReferencedDirection}, elements["Owners"].selectionCriteria.(*RelatedSelectionCriteria).Directions())
+
+	ymlSchema, err = NewYmlParser().Parse(strings.Replace(
+		ymlConfiguration,
+		"            Resource: Customers\n            SelectionCriteria:\n",
+		"            Resource: Customers\n            SelectionCriteria:\n              Direction: Referencing\n",
+		1,
+	))
+	assert.Nil(t, err)
+	_, err = NewConfigurationBuilderYml().Build(ymlSchema)
+	var relationDirectionMismatchError *RelationDirectionMismatchError
+	assert.True(t, errors.As(err, &relationDirectionMismatchError))
+	assert.Equal(t, "Customers", relationDirectionMismatchError.Resource)
+
+	validationErrors := Validate(parseYmlSchema(t, strings.Replace(ymlConfiguration, "Direction: Referenced", "Direction: Upwards", 1)))
+	assert.Len(t, validationErrors, 1)
+	assert.Equal(t, "$.Entities.Catalogue.Components.Categories.Elements.Parents.SelectionCriteria.Direction", validationErrors[0].Path)
+	var unknownRelationDirectionError *UnknownRelationDirectionError
+	assert.True(t, errors.As(validationErrors[0], &unknownRelationDirectionError))
+}
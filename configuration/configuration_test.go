@@ -0,0 +1,185 @@
+package configuration_test
+
+import (
+	"strings"
+	"testing"
+
+	"entity-works/configuration"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const accessorYmlConfiguration = `Name: Shop
+Description: Online shop
+Resources:
+  Orders:
+    TableName: orders
+    PrimaryKey:
+      - orders.id
+    AutoIncrement: true
+    Index:
+      Status:
+        - orders.status
+      Created:
+        - orders.created_at
+    ForeignKeys:
+      - Type: NORMAL
+        Key: orders.customer_id
+        ResourceName: Customers
+        ForeignKey: customers.id
+  Customers:
+    TableName: customers
+    PrimaryKey:
+      - customers.id
+Entities:
+  Sales:
+    Description: Sales entity
+    Components:
+      Orders:
+        Description: Orders component
+        Elements:
+          Orders:
+            Resource: Orders
+            SelectionCriteria:
+              Type: Related
+              Elements:
+                - Customers
+          Customers:
+            Resource: Customers
+            SelectionCriteria:
+              Type: Custom
+              Criteria: region = 'EU'
+      Archive:
+        Description: Archive component
+        Elements:
+          Customers:
+            Shares: Sales::Orders::Customers
+`
+
+func loadAccessorConfiguration(t *testing.T) *configuration.Configuration {
+	loadedConfiguration, err := configuration.LoadReader(strings.NewReader(accessorYmlConfiguration))
+	assert.Nil(t, err)
+
+	return loadedConfiguration
+}
+
+func TestConfigurationExposesResourcesInNameOrder(t *testing.T) {
+	loadedConfiguration := loadAccessorConfiguration(t)
+
+	assert.Equal(t, "Shop", loadedConfiguration.Name())
+	assert.Equal(t, "Online shop", loadedConfiguration.Description())
+	assert.Equal(t, []string{"Customers", "Orders"}, loadedConfiguration.ResourceNames())
+
+	resources := loadedConfiguration.Resources()
+	assert.Len(t, resources, 2)
+	assert.Equal(t, "Customers", resources[0].Name())
+	assert.Equal(t, "Orders", resources[1].Name())
+
+	orders, exists := loadedConfiguration.Resource("Orders")
+	assert.True(t, exists)
+	assert.Equal(t, "orders", orders.TableName())
+	assert.Equal(t, []configuration.ColumnRef{{Table: "orders", Column: "id"}}, orders.PrimaryKey())
+	assert.True(t, orders.AutoIncrement())
+	assert.Equal(t, []string{"Created", "Status"}, orders.IndexNames())
+	statusColumns, exists := orders.Index("Status")
+	assert.True(t, exists)
+	assert.Equal(t, []configuration.ColumnRef{{Table: "orders", Column: "status"}}, statusColumns)
+
+	foreignKeys := orders.ForeignKeys()
+	assert.Len(t, foreignKeys, 1)
+	assert.Equal(t, configuration.NormalKeyType, foreignKeys[0].KeyType())
+	assert.Equal(t, []configuration.ColumnRef{{Table: "orders", Column: "customer_id"}}, foreignKeys[0].Keys())
+	assert.Equal(t, "Customers", foreignKeys[0].ForeignResource())
+	assert.Equal(t, []configuration.ColumnRef{{Table: "customers", Column: "id"}}, foreignKeys[0].ForeignKeys())
+
+	_, exists = loadedConfiguration.Resource("Missing")
+	assert.False(t, exists)
+}
+
+func TestConfigurationAccessorsReturnCopies(t *testing.T) {
+	loadedConfiguration := loadAccessorConfiguration(t)
+	orders, _ := loadedConfiguration.Resource("Orders")
+
+	orders.PrimaryKey()[0] = configuration.ColumnRef{Table: "orders", Column: "changed"}
+
+	orders, _ = loadedConfiguration.Resource("Orders")
+	assert.Equal(t, []configuration.ColumnRef{{Table: "orders", Column: "id"}}, orders.PrimaryKey())
+}
+
+func TestConfigurationExposesRelationships(t *testing.T) {
+	relationships := loadAccessorConfiguration(t).Relationships()
+
+	from := relationships.From("Orders")
+	assert.Equal(t, []string{"Customers"}, from.Names())
+	relation, exists := from.Relation("Customers")
+	assert.True(t, exists)
+	assert.Equal(t, "orders", relation.FromTable())
+	assert.Equal(t, []configuration.ColumnRef{{Table: "orders", Column: "customer_id"}}, relation.FromKeys())
+	assert.Equal(t, "customers", relation.ToTable())
+	assert.Equal(t, []configuration.ColumnRef{{Table: "customers", Column: "id"}}, relation.ToKeys())
+	assert.Equal(t, configuration.NormalKeyType, relation.KeyType())
+
+	assert.Equal(t, []string{"Orders::Customers"}, relationships.To("Customers").Names())
+	assert.Empty(t, relationships.To("Orders").Names())
+}
+
+func TestConfigurationExposesEntitiesComponentsAndElements(t *testing.T) {
+	loadedConfiguration := loadAccessorConfiguration(t)
+
+	assert.Equal(t, []string{"Sales"}, loadedConfiguration.EntityNames())
+	sales, exists := loadedConfiguration.Entity("Sales")
+	assert.True(t, exists)
+	assert.Equal(t, "Sales", sales.Name())
+	assert.Equal(t, "Sales entity", sales.Description())
+	assert.Equal(t, []string{"Archive", "Orders"}, sales.ComponentNames())
+
+	components := sales.Components()
+	assert.Equal(t, "Archive", components[0].Name())
+	assert.Equal(t, "Orders component", components[1].Description())
+
+	elements := components[1].Elements()
+	assert.Len(t, elements, 2)
+	assert.Equal(t, "Customers", elements[0].Name())
+	assert.Equal(t, configuration.ElementPath{Entity: "Sales", Component: "Orders", Element: "Customers"}, elements[0].Path())
+	assert.Equal(t, "customers", elements[0].Resource().TableName())
+
+	customSelectionCriteria, isCustom := elements[0].SelectionCriteria().(*configuration.CustomSelectionCriteria)
+	assert.True(t, isCustom)
+	assert.Equal(t, "region = 'EU'", customSelectionCriteria.Criteria())
+
+	relatedSelectionCriteria, isRelated := elements[1].SelectionCriteria().(*configuration.RelatedSelectionCriteria)
+	assert.True(t, isRelated)
+	assert.Len(t, relatedSelectionCriteria.Elements(), 1)
+	assert.Equal(t, "Customers", relatedSelectionCriteria.Elements()[0].Name())
+
+	archivedCustomers, exists := loadedConfiguration.Element(configuration.ElementPath{
+		Entity:    "Sales",
+		Component: "Archive",
+		Element:   "Customers",
+	})
+	assert.True(t, exists)
+	sharedElementPath, shares := archivedCustomers.Shares()
+	assert.True(t, shares)
+	assert.Equal(t, "Sales::Orders::Customers", sharedElementPath.String())
+	assert.Equal(t, "customers", archivedCustomers.Resource().TableName())
+}
+
+func TestSelectionCriteriaReferenceTheBuiltElements(t *testing.T) {
+	loadedConfiguration := loadAccessorConfiguration(t)
+
+	orders, _ := loadedConfiguration.Element(configuration.ElementPath{Entity: "Sales", Component: "Orders", Element: "Orders"})
+	customers, _ := loadedConfiguration.Element(configuration.ElementPath{Entity: "Sales", Component: "Orders", Element: "Customers"})
+
+	relatedSelectionCriteria := orders.SelectionCriteria().(*configuration.RelatedSelectionCriteria)
+	relatedCustomers := relatedSelectionCriteria.Elements()[0]
+	assert.Same(t, customers, relatedCustomers)
+
+	customSelectionCriteria, isCustom := relatedCustomers.SelectionCriteria().(*configuration.CustomSelectionCriteria)
+	assert.True(t, isCustom)
+	assert.Equal(t, "region = 'EU'", customSelectionCriteria.Criteria())
+
+	sales, _ := loadedConfiguration.Entity("Sales")
+	component, _ := sales.Component("Orders")
+	componentCustomers, _ := component.Element("Customers")
+	assert.Same(t, customers, componentCustomers)
+}
@@ -0,0 +1,171 @@
+package configuration
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	placeholderOpen  = "{{"
+	placeholderClose = "}}"
+)
+
+type criteriaSegment struct {
+	text      string
+	parameter string
+}
+
+type CriteriaTemplate struct {
+	segments []criteriaSegment
+}
+
+func ParseCriteriaTemplate(criteria string) (CriteriaTemplate, error) {
+	var segments []criteriaSegment
+	var text strings.Builder
+	var quote byte
+	quoteStart := -1
+
+	for index := 0; index < len(criteria); {
+		character := criteria[index]
+		switch {
+		case quote != 0 && character == quote:
+			text.WriteByte(character)
+			if index+1 < len(criteria) && criteria[index+1] == quote {
+				text.WriteByte(quote)
+				index += 2
+				continue
+			}
+			quote = 0
+			index++
+
+		case strings.HasPrefix(criteria[index:], placeholderOpen):
+			parameter, length, err := parsePlaceholder(criteria[index:])
+			if err != nil {
+				return CriteriaTemplate{}, err
+			}
+
+			end := index + length
+			if quote != 0 {
+				fillsLiteral := quoteStart == index-1 &&
+					end < len(criteria) && criteria[end] == quote &&
+					(end+1 == len(criteria) || criteria[end+1] != quote)
+				if !fillsLiteral {
+					return CriteriaTemplate{}, fmt.Errorf(
+						"placeholder %q must be the whole string literal, not part of it",
+						criteria[index:end],
+					)
+				}
+
+				textBeforeQuote := text.String()
+				text.Reset()
+				text.WriteString(textBeforeQuote[:len(textBeforeQuote)-1])
+				quote = 0
+				end++
+			}
+
+			if text.Len() > 0 {
+				segments = append(segments, criteriaSegment{text: text.String()})
+			}
+			segments = append(segments, criteriaSegment{parameter: parameter})
+			text.Reset()
+			index = end
+
+		case quote == 0 && (character == '\'' || character == '"'):
+			quote = character
+			quoteStart = index
+			text.WriteByte(character)
+			index++
+
+		default:
+			text.WriteByte(character)
+			index++
+		}
+	}
+
+	if text.Len() > 0 {
+		segments = append(segments, criteriaSegment{text: text.String()})
+	}
+
+	return CriteriaTemplate{segments: segments}, nil
+}
+
+func parsePlaceholder(criteria string) (string, int, error) {
+	closeIndex := strings.Index(criteria, placeholderClose)
+	if closeIndex < 0 {
+		return "", 0, fmt.Errorf("placeholder in %q is not closed with %s", criteria, placeholderClose)
+	}
+
+	parameter := strings.TrimSpace(criteria[len(placeholderOpen):closeIndex])
+	if !isParameterName(parameter) {
+		return "", 0, fmt.Errorf("placeholder %q has an invalid parameter name", criteria[:closeIndex+len(placeholderClose)])
+	}
+
+	return parameter, closeIndex + len(placeholderClose), nil
+}
+
+func isParameterName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for index, character := range name {
+		isLetter := character == '_' || (character >= 'a' && character <= 'z') || (character >= 'A' && character <= 'Z')
+		isDigit := character >= '0' && character <= '9'
+		if !isLetter && (index == 0 || !isDigit) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (criteriaTemplate CriteriaTemplate) Parameters() []string {
+	seen := make(map[string]bool)
+	var parameters []string
+	for _, segment := range criteriaTemplate.segments {
+		if segment.parameter != "" && !seen[segment.parameter] {
+			seen[segment.parameter] = true
+			parameters = append(parameters, segment.parameter)
+		}
+	}
+
+	return parameters
+}
+
+func (criteriaTemplate CriteriaTemplate) Render(
+	values map[string]any,
+	placeholder func(position int) string,
+) (string, []any, error) {
+	var sql strings.Builder
+	var args []any
+	for _, segment := range criteriaTemplate.segments {
+		if segment.parameter == "" {
+			sql.WriteString(segment.text)
+			continue
+		}
+
+		value, exists := values[segment.parameter]
+		if !exists {
+			return "", nil, &MissingParameterError{Parameter: segment.parameter}
+		}
+
+		args = append(args, value)
+		sql.WriteString(placeholder(len(args)))
+	}
+
+	return sql.String(), args, nil
+}
+
+func (criteriaTemplate CriteriaTemplate) String() string {
+	var criteria strings.Builder
+	for _, segment := range criteriaTemplate.segments {
+		if segment.parameter == "" {
+			criteria.WriteString(segment.text)
+			continue
+		}
+
+		criteria.WriteString(placeholderOpen + segment.parameter + placeholderClose)
+	}
+
+	return criteria.String()
+}
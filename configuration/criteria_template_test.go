@@ -0,0 +1,69 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func questionMark(int) string {
+	return "?"
+}
+
+func TestCriteriaTemplateBindsPlaceholders(t *testing.T) {
+	template, err := ParseCriteriaTemplate(`region = "{{region}}" AND created_at > {{ since }} AND name <> 'it''s' AND tier = '{{region}}'`)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"region", "since"}, template.Parameters())
+
+	sql, args, err := template.Render(map[string]any{"region": `EU" OR 1 = 1 --`, "since": int64(2020)}, questionMark)
+	assert.Nil(t, err)
+	assert.Equal(t, `region = ? AND created_at > ? AND name <> 'it''s' AND tier = ?`, sql)
+	assert.Equal(t, []any{`EU" OR 1 = 1 --`, int64(2020), `EU" OR 1 = 1 --`}, args)
+}
+
+func TestCriteriaTemplateNumbersPlaceholders(t *testing.T) {
+	template, err := ParseCriteriaTemplate(`a = {{first}} OR b = {{second}}`)
+	assert.Nil(t, err)
+
+	sql, _, err := template.Render(map[string]any{"first": 1, "second": 2}, func(position int) string {
+		return fmt.Sprintf("$%d", position)
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "a = $1 OR b = $2", sql)
+	assert.Equal(t, "a = {{first}} OR b = {{second}}", template.String())
+}
+
+func TestCriteriaTemplateWithoutPlaceholders(t *testing.T) {
+	template, err := ParseCriteriaTemplate(`note = '{ not a placeholder }'`)
+	assert.Nil(t, err)
+	assert.Empty(t, template.Parameters())
+
+	sql, args, err := template.Render(nil, questionMark)
+	assert.Nil(t, err)
+	assert.Equal(t, `note = '{ not a placeholder }'`, sql)
+	assert.Empty(t, args)
+}
+
+func TestCriteriaTemplateRejectsInvalidPlaceholders(t *testing.T) {
+	for _, criteria := range []string{
+		`region = {{region`,
+		`region = {{}}`,
+		`region = {{1region}}`,
+		`name LIKE '%{{name}}%'`,
+	} {
+		_, err := ParseCriteriaTemplate(criteria)
+		assert.NotNil(t, err, criteria)
+	}
+}
+
+func TestCriteriaTemplateReportsMissingValues(t *testing.T) {
+	template, err := ParseCriteriaTemplate(`region = {{region}}`)
+	assert.Nil(t, err)
+
+	_, _, err = template.Render(map[string]any{}, questionMark)
+	var missingParameterError *MissingParameterError
+	assert.True(t, errors.As(err, &missingParameterError))
+	assert.Equal(t, "region", missingParameterError.Parameter)
+}
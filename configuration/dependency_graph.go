@@ -0,0 +1,158 @@
+package configuration
+
+type DependencyGraph struct {
+	elements   []ElementPath
+	upstream   map[ElementPath][]ElementPath
+	downstream map[ElementPath][]ElementPath
+	order      []ElementPath
+}
+
+func NewDependencyGraph(ymlEntities map[string]YmlEntity) (*DependencyGraph, error) {
+	dependencyGraph := &DependencyGraph{
+		upstream:   make(map[ElementPath][]ElementPath),
+		downstream: make(map[ElementPath][]ElementPath),
+	}
+
+	for _, entityName := range sortedKeys(ymlEntities) {
+		ymlComponents := ymlEntities[entityName].Components
+		for _, componentName := range sortedKeys(ymlComponents) {
+			ymlElements := ymlComponents[componentName].Elements
+			for _, elementName := range sortedKeys(ymlElements) {
+				elementPath := *NewElementPath(entityName, componentName, elementName)
+				dependencyGraph.elements = append(dependencyGraph.elements, elementPath)
+				for _, upstreamElementPath := range ymlElementDependencies(ymlEntities, elementPath, ymlElements[elementName]) {
+					dependencyGraph.addEdge(upstreamElementPath, elementPath)
+				}
+			}
+		}
+	}
+
+	if err := dependencyGraph.sort(); err != nil {
+		return nil, err
+	}
+
+	return dependencyGraph, nil
+}
+
+func ymlElementDependencies(ymlEntities map[string]YmlEntity, elementPath ElementPath, ymlElement YmlElement) []ElementPath {
+	var dependencies []ElementPath
+	if ymlElement.Shares != "" {
+		sharedElementPath, err := ParseElementPath(ymlElement.Shares)
+		if _, exists := lookupYmlElement(ymlEntities, sharedElementPath); err == nil && exists {
+			dependencies = append(dependencies, sharedElementPath)
+		}
+	}
+
+	for _, relatedElementName := range ymlElement.SelectionCriteria.Elements {
+		relatedElementPath := *NewElementPath(elementPath.Entity, elementPath.Component, relatedElementName)
+		if _, exists := lookupYmlElement(ymlEntities, relatedElementPath); exists {
+			dependencies = append(dependencies, relatedElementPath)
+		}
+	}
+
+	return dependencies
+}
+
+func (dependencyGraph *DependencyGraph) addEdge(upstream ElementPath, downstream ElementPath) {
+	for _, existing := range dependencyGraph.upstream[downstream] {
+		if existing == upstream {
+			return
+		}
+	}
+
+	dependencyGraph.upstream[downstream] = append(dependencyGraph.upstream[downstream], upstream)
+	dependencyGraph.downstream[upstream] = append(dependencyGraph.downstream[upstream], downstream)
+}
+
+func (dependencyGraph *DependencyGraph) sort() error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	states := make(map[ElementPath]int)
+	var stack []ElementPath
+	var visit func(elementPath ElementPath) error
+	visit = func(elementPath ElementPath) error {
+		switch states[elementPath] {
+		case visited:
+			return nil
+		case visiting:
+			for index, stackedElementPath := range stack {
+				if stackedElementPath == elementPath {
+					var cycle []string
+					for _, cycleElementPath := range append(stack[index:], elementPath) {
+						cycle = append(cycle, cycleElementPath.String())
+					}
+					return &CyclicElementDependencyError{Elements: cycle}
+				}
+			}
+		}
+
+		states[elementPath] = visiting
+		stack = append(stack, elementPath)
+		for _, upstreamElementPath := range dependencyGraph.upstream[elementPath] {
+			if err := visit(upstreamElementPath); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		states[elementPath] = visited
+		dependencyGraph.order = append(dependencyGraph.order, elementPath)
+
+		return nil
+	}
+
+	for _, elementPath := range dependencyGraph.elements {
+		if err := visit(elementPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (dependencyGraph DependencyGraph) Order() []ElementPath {
+	return append([]ElementPath(nil), dependencyGraph.order...)
+}
+
+func (dependencyGraph DependencyGraph) Upstream(elementPath ElementPath) []ElementPath {
+	return append([]ElementPath(nil), dependencyGraph.upstream[elementPath]...)
+}
+
+func (dependencyGraph DependencyGraph) Downstream(elementPath ElementPath) []ElementPath {
+	return append([]ElementPath(nil), dependencyGraph.downstream[elementPath]...)
+}
+
+func (dependencyGraph DependencyGraph) AllUpstream(elementPath ElementPath) []ElementPath {
+	return dependencyGraph.closure(elementPath, dependencyGraph.upstream)
+}
+
+func (dependencyGraph DependencyGraph) AllDownstream(elementPath ElementPath) []ElementPath {
+	return dependencyGraph.closure(elementPath, dependencyGraph.downstream)
+}
+
+func (dependencyGraph DependencyGraph) closure(elementPath ElementPath, edges map[ElementPath][]ElementPath) []ElementPath {
+	reached := make(map[ElementPath]bool)
+	pending := append([]ElementPath(nil), edges[elementPath]...)
+	for len(pending) > 0 {
+		next := pending[0]
+		pending = pending[1:]
+		if reached[next] {
+			continue
+		}
+
+		reached[next] = true
+		pending = append(pending, edges[next]...)
+	}
+
+	var closure []ElementPath
+	for _, orderedElementPath := range dependencyGraph.order {
+		if reached[orderedElementPath] {
+			closure = append(closure, orderedElementPath)
+		}
+	}
+
+	return closure
+}
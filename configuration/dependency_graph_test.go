@@ -0,0 +1,103 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const dependencyGraphYmlConfiguration = `Name: Example
+Resources:
+  MyTestResource:
+    TableName: my_test_table
+    ForeignKeys:
+      - Type: NORMAL
+        Key: my_test_table.parent_id
+        ResourceName: MyTestResource
+        ForeignKey: my_test_table.id
+Entities:
+  MyTestEntity:
+    Components:
+      MyTestComponent1:
+        Elements:
+          ElementA:
+            Resource: MyTestResource
+            SelectionCriteria:
+              Type: Custom
+              Criteria: 1 = 1
+          ElementB:
+            Resource: MyTestResource
+            SelectionCriteria:
+              Type: Related
+              Elements:
+                - ElementC
+          ElementC:
+            Resource: MyTestResource
+            SelectionCriteria:
+              Type: Related
+              Elements:
+                - ElementA
+      MyTestComponent2:
+        Elements:
+          ElementB:
+            Shares: MyTestEntity::MyTestComponent1::ElementB
+          ElementD:
+            Resource: MyTestResource
+            SelectionCriteria:
+              Type: Related
+              Elements:
+                - ElementB
+`
+
+func dependencyGraphElementPath(component string, element string) ElementPath {
+	return *NewElementPath("MyTestEntity", component, element)
+}
+
+func TestDependencyGraphOrdersElementsTopologically(t *testing.T) {
+	dependencyGraph, err := NewDependencyGraph(parseYmlSchema(t, dependencyGraphYmlConfiguration).Entities)
+	assert.Nil(t, err)
+
+	assert.Equal(
+		t,
+		[]ElementPath{
+			dependencyGraphElementPath("MyTestComponent1", "ElementA"),
+			dependencyGraphElementPath("MyTestComponent1", "ElementC"),
+			dependencyGraphElementPath("MyTestComponent1", "ElementB"),
+			dependencyGraphElementPath("MyTestComponent2", "ElementB"),
+			dependencyGraphElementPath("MyTestComponent2", "ElementD"),
+		},
+		dependencyGraph.Order(),
+	)
+}
+
+func TestDependencyGraphReportsUpstreamAndDownstreamElements(t *testing.T) {
+	dependencyGraph, err := NewDependencyGraph(parseYmlSchema(t, dependencyGraphYmlConfiguration).Entities)
+	assert.Nil(t, err)
+
+	elementA := dependencyGraphElementPath("MyTestComponent1", "ElementA")
+	elementB := dependencyGraphElementPath("MyTestComponent1", "ElementB")
+	elementC := dependencyGraphElementPath("MyTestComponent1", "ElementC")
+	sharedElementB := dependencyGraphElementPath("MyTestComponent2", "ElementB")
+	elementD := dependencyGraphElementPath("MyTestComponent2", "ElementD")
+
+	assert.Empty(t, dependencyGraph.Upstream(elementA))
+	assert.Equal(t, []ElementPath{elementC}, dependencyGraph.Downstream(elementA))
+	assert.Equal(t, []ElementPath{elementB}, dependencyGraph.Upstream(sharedElementB))
+	assert.Equal(t, []ElementPath{sharedElementB}, dependencyGraph.Upstream(elementD))
+	assert.Equal(t, []ElementPath{elementA, elementC, elementB, sharedElementB}, dependencyGraph.AllUpstream(elementD))
+	assert.Equal(t, []ElementPath{elementC, elementB, sharedElementB, elementD}, dependencyGraph.AllDownstream(elementA))
+}
+
+func TestConfigurationBuilderBuildsElementsInDependencyOrder(t *testing.T) {
+	configuration, err := NewConfigurationBuilderYml().Build(parseYmlSchema(t, dependencyGraphYmlConfiguration))
+	assert.Nil(t, err)
+
+	elementB, _ := configuration.Element(dependencyGraphElementPath("MyTestComponent1", "ElementB"))
+	elementC := elementB.selectionCriteria.(*RelatedSelectionCriteria).elements[0]
+	assert.Equal(t, "ElementC", elementC.Name())
+	assert.IsType(t, &RelatedSelectionCriteria{}, elementC.selectionCriteria)
+	assert.Equal(t, "ElementA", elementC.selectionCriteria.(*RelatedSelectionCriteria).elements[0].Name())
+
+	dependencyGraph := configuration.DependencyGraph()
+	assert.Len(t, dependencyGraph.Order(), 5)
+}
@@ -0,0 +1,390 @@
+package configuration
+
+import (
+	"fmt"
+	"strings"
+)
+
+type UnknownResourceError struct {
+	Resource string
+}
+
+func (unknownResourceError *UnknownResourceError) Error() string {
+	return fmt.Sprintf("unknown resource %q", unknownResourceError.Resource)
+}
+
+type UnknownElementError struct {
+	Element string
+}
+
+func (unknownElementError *UnknownElementError) Error() string {
+	return fmt.Sprintf("unknown element %q", unknownElementError.Element)
+}
+
+type UnknownSelectionCriteriaTypeError struct {
+	Type string
+}
+
+func (unknownSelectionCriteriaTypeError *UnknownSelectionCriteriaTypeError) Error() string {
+	return fmt.Sprintf("unknown selection criteria type %q", unknownSelectionCriteriaTypeError.Type)
+}
+
+type CyclicElementDependencyError struct {
+	Elements []string
+}
+
+func (cyclicElementDependencyError *CyclicElementDependencyError) Error() string {
+	return fmt.Sprintf("cyclic element dependency: %s", strings.Join(cyclicElementDependencyError.Elements, " -> "))
+}
+
+type CyclicResourceDependencyError struct {
+	Resources []string
+}
+
+func (cyclicResourceDependencyError *CyclicResourceDependencyError) Error() string {
+	return fmt.Sprintf("cyclic resource dependency: %s", strings.Join(cyclicResourceDependencyError.Resources, " -> "))
+}
+
+type DuplicateResourceError struct {
+	Resource string
+	Files    []string
+}
+
+func (duplicateResourceError *DuplicateResourceError) Error() string {
+	return fmt.Sprintf(
+		"resource %q is defined more than once (in %s)",
+		duplicateResourceError.Resource,
+		strings.Join(duplicateResourceError.Files, ", "),
+	)
+}
+
+type DuplicateEntityError struct {
+	Entity string
+	Files  []string
+}
+
+func (duplicateEntityError *DuplicateEntityError) Error() string {
+	return fmt.Sprintf(
+		"entity %q is defined more than once (in %s)",
+		duplicateEntityError.Entity,
+		strings.Join(duplicateEntityError.Files, ", "),
+	)
+}
+
+type DuplicateComponentError struct {
+	Entity    string
+	Component string
+	Files     []string
+}
+
+func (duplicateComponentError *DuplicateComponentError) Error() string {
+	return fmt.Sprintf(
+		"component %q of entity %q is defined more than once (in %s)",
+		duplicateComponentError.Component,
+		duplicateComponentError.Entity,
+		strings.Join(duplicateComponentError.Files, ", "),
+	)
+}
+
+type IncludeCycleError struct {
+	Files []string
+}
+
+func (includeCycleError *IncludeCycleError) Error() string {
+	return fmt.Sprintf("include cycle: %s", strings.Join(includeCycleError.Files, " -> "))
+}
+
+type SharedResourceMismatchError struct {
+	Element        string
+	Resource       string
+	SharedElement  string
+	SharedResource string
+}
+
+func (sharedResourceMismatchError *SharedResourceMismatchError) Error() string {
+	return fmt.Sprintf(
+		"element %q uses resource %q but shares %q which uses resource %q",
+		sharedResourceMismatchError.Element,
+		sharedResourceMismatchError.Resource,
+		sharedResourceMismatchError.SharedElement,
+		sharedResourceMismatchError.SharedResource,
+	)
+}
+
+type InvalidReferenceError struct {
+	Reference string
+	Err       error
+}
+
+func (invalidReferenceError *InvalidReferenceError) Error() string {
+	return fmt.Sprintf("invalid reference %q: %s", invalidReferenceError.Reference, invalidReferenceError.Err)
+}
+
+func (invalidReferenceError *InvalidReferenceError) Unwrap() error {
+	return invalidReferenceError.Err
+}
+
+type ColumnTableMismatchError struct {
+	Column   ColumnRef
+	Resource string
+	Table    TableRef
+}
+
+func (columnTableMismatchError *ColumnTableMismatchError) Error() string {
+	return fmt.Sprintf(
+		"column %q does not belong to table %q of resource %q",
+		columnTableMismatchError.Column.String(),
+		columnTableMismatchError.Table.String(),
+		columnTableMismatchError.Resource,
+	)
+}
+
+type ForeignKeyArityError struct {
+	Keys        int
+	ForeignKeys int
+}
+
+func (foreignKeyArityError *ForeignKeyArityError) Error() string {
+	return fmt.Sprintf(
+		"foreign key has %d key column(s) but %d foreign key column(s)",
+		foreignKeyArityError.Keys,
+		foreignKeyArityError.ForeignKeys,
+	)
+}
+
+type DuplicateForeignKeyError struct {
+	Resource   string
+	ForeignKey string
+}
+
+func (duplicateForeignKeyError *DuplicateForeignKeyError) Error() string {
+	return fmt.Sprintf(
+		"resource %q has more than one foreign key named %q",
+		duplicateForeignKeyError.Resource,
+		duplicateForeignKeyError.ForeignKey,
+	)
+}
+
+type NoRelationError struct {
+	Resource        string
+	RelatedResource string
+	Via             string
+}
+
+func (noRelationError *NoRelationError) Error() string {
+	if noRelationError.Via != "" {
+		return fmt.Sprintf(
+			"no foreign key named %q relates resource %q to resource %q",
+			noRelationError.Via,
+			noRelationError.Resource,
+			noRelationError.RelatedResource,
+		)
+	}
+
+	return fmt.Sprintf(
+		"no foreign key relates resource %q to resource %q",
+		noRelationError.Resource,
+		noRelationError.RelatedResource,
+	)
+}
+
+type AmbiguousRelationError struct {
+	Resource        string
+	RelatedResource string
+	Relations       []string
+}
+
+func (ambiguousRelationError *AmbiguousRelationError) Error() string {
+	return fmt.Sprintf(
+		"resource %q is related to resource %q by several foreign keys (%s), choose one with Via",
+		ambiguousRelationError.Resource,
+		ambiguousRelationError.RelatedResource,
+		strings.Join(ambiguousRelationError.Relations, ", "),
+	)
+}
+
+type UnknownKeyTypeError struct {
+	Type string
+}
+
+func (unknownKeyTypeError *UnknownKeyTypeError) Error() string {
+	return fmt.Sprintf("unknown foreign key type %q, expected NORMAL or DELIMITED", unknownKeyTypeError.Type)
+}
+
+type UnknownDelimitedFormatError struct {
+	Format string
+}
+
+func (unknownDelimitedFormatError *UnknownDelimitedFormatError) Error() string {
+	return fmt.Sprintf("unknown delimited key format %q, expected TEXT, JSON or ARRAY", unknownDelimitedFormatError.Format)
+}
+
+type InvalidDelimiterError struct {
+	Format    DelimitedFormat
+	Delimiter string
+}
+
+func (invalidDelimiterError *InvalidDelimiterError) Error() string {
+	if invalidDelimiterError.Format == JSONDelimitedFormat {
+		return fmt.Sprintf("delimiter %q cannot be used with JSON delimited keys", invalidDelimiterError.Delimiter)
+	}
+
+	return fmt.Sprintf(
+		"delimiter %q of %s delimited keys must be a single character",
+		invalidDelimiterError.Delimiter,
+		invalidDelimiterError.Format,
+	)
+}
+
+type DelimitedKeyArityError struct {
+	Keys int
+}
+
+func (delimitedKeyArityError *DelimitedKeyArityError) Error() string {
+	return fmt.Sprintf("delimited foreign key must have exactly one key column, got %d", delimitedKeyArityError.Keys)
+}
+
+type DelimitedOptionError struct {
+	Option string
+}
+
+func (delimitedOptionError *DelimitedOptionError) Error() string {
+	return fmt.Sprintf("%s only applies to DELIMITED foreign keys", delimitedOptionError.Option)
+}
+
+type UnknownFieldError struct {
+	Field      string
+	Suggestion string
+}
+
+func (unknownFieldError *UnknownFieldError) Error() string {
+	if unknownFieldError.Suggestion != "" {
+		return fmt.Sprintf("unknown field %q, did you mean %q?", unknownFieldError.Field, unknownFieldError.Suggestion)
+	}
+
+	return fmt.Sprintf("unknown field %q", unknownFieldError.Field)
+}
+
+type UnknownRecursionDirectionError struct {
+	Direction string
+}
+
+func (unknownRecursionDirectionError *UnknownRecursionDirectionError) Error() string {
+	return fmt.Sprintf(
+		"unknown recursion direction %q, expected Ancestors, Descendants or Both",
+		unknownRecursionDirectionError.Direction,
+	)
+}
+
+type UnknownRelationDirectionError struct {
+	Direction string
+}
+
+func (unknownRelationDirectionError *UnknownRelationDirectionError) Error() string {
+	return fmt.Sprintf(
+		"unknown relation direction %q, expected Referencing or Referenced",
+		unknownRelationDirectionError.Direction,
+	)
+}
+
+type RelationDirectionMismatchError struct {
+	Resource  string
+	Relation  string
+	Direction RelationDirection
+}
+
+func (relationDirectionMismatchError *RelationDirectionMismatchError) Error() string {
+	if relationDirectionMismatchError.Direction == ReferencingDirection {
+		return fmt.Sprintf(
+			"resource %q does not hold the key of relation %q, it cannot be Referencing",
+			relationDirectionMismatchError.Resource,
+			relationDirectionMismatchError.Relation,
+		)
+	}
+
+	return fmt.Sprintf(
+		"resource %q is not referenced by relation %q, it cannot be Referenced",
+		relationDirectionMismatchError.Resource,
+		relationDirectionMismatchError.Relation,
+	)
+}
+
+type UnknownParameterTypeError struct {
+	Parameter string
+	Type      string
+}
+
+func (unknownParameterTypeError *UnknownParameterTypeError) Error() string {
+	return fmt.Sprintf(
+		"parameter %q has unknown type %q, expected String, Integer, Number or Boolean",
+		unknownParameterTypeError.Parameter,
+		unknownParameterTypeError.Type,
+	)
+}
+
+type UnknownParameterError struct {
+	Parameter string
+}
+
+func (unknownParameterError *UnknownParameterError) Error() string {
+	return fmt.Sprintf("unknown parameter %q", unknownParameterError.Parameter)
+}
+
+type MissingParameterError struct {
+	Parameter string
+}
+
+func (missingParameterError *MissingParameterError) Error() string {
+	return fmt.Sprintf("missing value for parameter %q", missingParameterError.Parameter)
+}
+
+type InvalidParameterValueError struct {
+	Parameter string
+	Type      ParameterType
+	Value     any
+	Err       error
+}
+
+func (invalidParameterValueError *InvalidParameterValueError) Error() string {
+	return fmt.Sprintf(
+		"value %v of parameter %q is not a valid %s: %s",
+		invalidParameterValueError.Value,
+		invalidParameterValueError.Parameter,
+		invalidParameterValueError.Type,
+		invalidParameterValueError.Err,
+	)
+}
+
+func (invalidParameterValueError *InvalidParameterValueError) Unwrap() error {
+	return invalidParameterValueError.Err
+}
+
+type UnknownIndexError struct {
+	Resource string
+	Index    string
+}
+
+func (unknownIndexError *UnknownIndexError) Error() string {
+	return fmt.Sprintf("resource %q has no index %q", unknownIndexError.Resource, unknownIndexError.Index)
+}
+
+type IndexArityError struct {
+	Resource         string
+	Index            string
+	Columns          int
+	Upstream         string
+	UpstreamResource string
+	UpstreamColumns  int
+}
+
+func (indexArityError *IndexArityError) Error() string {
+	return fmt.Sprintf(
+		"index %q of resource %q has %d column(s) but element %q of resource %q has %d primary key column(s)",
+		indexArityError.Index,
+		indexArityError.Resource,
+		indexArityError.Columns,
+		indexArityError.Upstream,
+		indexArityError.UpstreamResource,
+		indexArityError.UpstreamColumns,
+	)
+}
@@ -0,0 +1,100 @@
+package configuration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const indexedYmlConfiguration = `Name: Shop
+Resources:
+  Regions:
+    TableName: regions
+    PrimaryKey:
+      - regions.region
+  Customers:
+    TableName: customers
+    PrimaryKey:
+      - customers.id
+    Index:
+      Region:
+        - customers.region
+      RegionAndTier:
+        - customers.region
+        - customers.tier
+Entities:
+  CoreProduct:
+    Components:
+      Customers:
+        Elements:
+          Regions:
+            Resource: Regions
+            SelectionCriteria:
+              Type: Custom
+              Criteria: region = 'EU'
+          Customers:
+            Resource: Customers
+            SelectionCriteria:
+              Type: Index
+              Index: Region
+              Elements:
+                - Regions
+`
+
+func TestIndexedSelectionCriteriaMapsIndexToUpstreamKey(t *testing.T) {
+	configuration, err := NewConfigurationBuilderYml().Build(parseYmlSchema(t, indexedYmlConfiguration))
+	assert.Nil(t, err)
+
+	customers, _ := configuration.Element(ElementPath{Entity: "CoreProduct", Component: "Customers", Element: "Customers"})
+	regions, _ := configuration.Element(ElementPath{Entity: "CoreProduct", Component: "Customers", Element: "Regions"})
+	indexedSelectionCriteria := customers.SelectionCriteria().(*IndexedSelectionCriteria)
+
+	assert.Equal(t, "Region", indexedSelectionCriteria.Index())
+	assert.Equal(t, []ColumnRef{{Table: "customers", Column: "region"}}, indexedSelectionCriteria.Columns())
+
+	mappings := indexedSelectionCriteria.Mappings()
+	assert.Len(t, mappings, 1)
+	assert.Same(t, regions, mappings[0].Upstream)
+	assert.Equal(t, []ColumnRef{{Table: "customers", Column: "region"}}, mappings[0].Columns)
+	assert.Equal(t, []ColumnRef{{Table: "regions", Column: "region"}}, mappings[0].UpstreamColumns)
+}
+
+func TestValidateReportsIndexArityMismatch(t *testing.T) {
+	ymlSchema := parseYmlSchema(t, indexedYmlConfiguration+`          TieredCustomers:
+            Resource: Customers
+            SelectionCriteria:
+              Type: Index
+              Index: RegionAndTier
+              Elements:
+                - Regions
+`)
+
+	validationErrors := Validate(ymlSchema)
+
+	assert.Len(t, validationErrors, 1)
+	assert.Equal(t, "$.Entities.CoreProduct.Components.Customers.Elements.TieredCustomers.SelectionCriteria.Elements[0]", validationErrors[0].Path)
+	var indexArityError *IndexArityError
+	assert.True(t, errors.As(validationErrors[0], &indexArityError))
+	assert.Equal(t, 2, indexArityError.Columns)
+	assert.Equal(t, 1, indexArityError.UpstreamColumns)
+}
+
+func TestValidateReportsUnknownIndex(t *testing.T) {
+	ymlSchema := parseYmlSchema(t, indexedYmlConfiguration+`          PremiumCustomers:
+            Resource: Customers
+            SelectionCriteria:
+              Type: Index
+              Index: Premium
+              Elements:
+                - Regions
+`)
+
+	validationErrors := Validate(ymlSchema)
+
+	assert.Len(t, validationErrors, 1)
+	assert.Equal(t, "$.Entities.CoreProduct.Components.Customers.Elements.PremiumCustomers.SelectionCriteria.Index", validationErrors[0].Path)
+	var unknownIndexError *UnknownIndexError
+	assert.True(t, errors.As(validationErrors[0], &unknownIndexError))
+	assert.Equal(t, "Premium", unknownIndexError.Index)
+}
@@ -0,0 +1,283 @@
+package configuration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type KeyType string
+
+const (
+	NormalKeyType    KeyType = "NORMAL"
+	DelimitedKeyType KeyType = "DELIMITED"
+)
+
+type DelimitedFormat string
+
+const (
+	TextDelimitedFormat  DelimitedFormat = "TEXT"
+	JSONDelimitedFormat  DelimitedFormat = "JSON"
+	ArrayDelimitedFormat DelimitedFormat = "ARRAY"
+)
+
+const defaultDelimiter = ","
+
+type DelimitedKey struct {
+	Format    DelimitedFormat
+	Delimiter string
+	Trim      bool
+}
+
+func parseKeyType(ymlForeignKey YmlForeignKey) (KeyType, DelimitedKey, error) {
+	switch KeyType(ymlForeignKey.Type) {
+	case "", NormalKeyType:
+		if option := delimitedOption(ymlForeignKey); option != "" {
+			return "", DelimitedKey{}, &DelimitedOptionError{Option: option}
+		}
+
+		return NormalKeyType, DelimitedKey{}, nil
+
+	case DelimitedKeyType:
+		if len(ymlForeignKey.Key) > 1 {
+			return "", DelimitedKey{}, &DelimitedKeyArityError{Keys: len(ymlForeignKey.Key)}
+		}
+
+		delimitedKey, err := NewDelimitedKey(ymlForeignKey.Format, ymlForeignKey.Delimiter, ymlForeignKey.Trim)
+		if err != nil {
+			return "", DelimitedKey{}, err
+		}
+
+		return DelimitedKeyType, delimitedKey, nil
+	}
+
+	return "", DelimitedKey{}, &UnknownKeyTypeError{Type: ymlForeignKey.Type}
+}
+
+func delimitedOption(ymlForeignKey YmlForeignKey) string {
+	switch {
+	case ymlForeignKey.Format != "":
+		return "Format"
+	case ymlForeignKey.Delimiter != "":
+		return "Delimiter"
+	case ymlForeignKey.Trim:
+		return "Trim"
+	}
+
+	return ""
+}
+
+func NewDelimitedKey(format string, delimiter string, trim bool) (DelimitedKey, error) {
+	delimitedKey := DelimitedKey{
+		Format:    DelimitedFormat(format),
+		Delimiter: delimiter,
+		Trim:      trim,
+	}
+
+	if delimitedKey.Format == "" {
+		delimitedKey.Format = TextDelimitedFormat
+	}
+
+	switch delimitedKey.Format {
+	case TextDelimitedFormat:
+		if delimitedKey.Delimiter == "" {
+			delimitedKey.Delimiter = defaultDelimiter
+		}
+
+	case ArrayDelimitedFormat:
+		if delimitedKey.Delimiter == "" {
+			delimitedKey.Delimiter = defaultDelimiter
+		}
+		if utf8.RuneCountInString(delimitedKey.Delimiter) != 1 {
+			return DelimitedKey{}, &InvalidDelimiterError{Format: delimitedKey.Format, Delimiter: delimitedKey.Delimiter}
+		}
+
+	case JSONDelimitedFormat:
+		if delimitedKey.Delimiter != "" {
+			return DelimitedKey{}, &InvalidDelimiterError{Format: delimitedKey.Format, Delimiter: delimitedKey.Delimiter}
+		}
+
+	default:
+		return DelimitedKey{}, &UnknownDelimitedFormatError{Format: format}
+	}
+
+	return delimitedKey, nil
+}
+
+func (delimitedKey DelimitedKey) EffectiveDelimiter() string {
+	if delimitedKey.Delimiter == "" && delimitedKey.Format != JSONDelimitedFormat {
+		return defaultDelimiter
+	}
+
+	return delimitedKey.Delimiter
+}
+
+func (delimitedKey DelimitedKey) Split(value string) ([]string, error) {
+	var values []string
+	var err error
+	switch delimitedKey.Format {
+	case JSONDelimitedFormat:
+		values, err = splitJSONArray(value)
+	case ArrayDelimitedFormat:
+		values, err = splitArray(value, []rune(delimitedKey.EffectiveDelimiter())[0])
+	default:
+		values = strings.Split(value, delimitedKey.EffectiveDelimiter())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(values))
+	for _, key := range values {
+		if delimitedKey.Trim {
+			key = strings.TrimSpace(key)
+		}
+		if key == "" {
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (delimitedKey DelimitedKey) Join(keys []string) string {
+	switch delimitedKey.Format {
+	case JSONDelimitedFormat:
+		elements := make([]string, 0, len(keys))
+		for _, key := range keys {
+			if isJSONNumber(key) {
+				elements = append(elements, key)
+				continue
+			}
+
+			element, _ := json.Marshal(key)
+			elements = append(elements, string(element))
+		}
+		return "[" + strings.Join(elements, ",") + "]"
+
+	case ArrayDelimitedFormat:
+		elements := make([]string, 0, len(keys))
+		for _, key := range keys {
+			elements = append(elements, quoteArrayElement(key, delimitedKey.EffectiveDelimiter()))
+		}
+		return "{" + strings.Join(elements, delimitedKey.EffectiveDelimiter()) + "}"
+	}
+
+	return strings.Join(keys, delimitedKey.EffectiveDelimiter())
+}
+
+func isJSONNumber(key string) bool {
+	if key == "" || (key[0] != '-' && (key[0] < '0' || key[0] > '9')) {
+		return false
+	}
+
+	return json.Valid([]byte(key))
+}
+
+func quoteArrayElement(key string, delimiter string) string {
+	if key != "" && !strings.EqualFold(key, "NULL") && !strings.ContainsAny(key, delimiter+"\"{}\\ \t\n") {
+		return key
+	}
+
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(key) + `"`
+}
+
+func splitJSONArray(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(value)))
+	decoder.UseNumber()
+	var elements []any
+	if err := decoder.Decode(&elements); err != nil {
+		return nil, fmt.Errorf("delimited key %q is not a JSON array: %w", value, err)
+	}
+
+	var values []string
+	for _, element := range elements {
+		switch element := element.(type) {
+		case nil:
+		case string:
+			values = append(values, element)
+		case json.Number:
+			values = append(values, element.String())
+		case bool:
+			values = append(values, strconv.FormatBool(element))
+		default:
+			return nil, fmt.Errorf("delimited key %q contains a nested JSON value", value)
+		}
+	}
+
+	return values, nil
+}
+
+func splitArray(value string, delimiter rune) ([]string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(value, "{") || !strings.HasSuffix(value, "}") {
+		return nil, fmt.Errorf("delimited key %q is not an array literal", value)
+	}
+
+	runes := []rune(value[1 : len(value)-1])
+	if len(runes) == 0 {
+		return nil, nil
+	}
+
+	var values []string
+	var element strings.Builder
+	quoted := false
+	wasQuoted := false
+	appendElement := func() {
+		if wasQuoted {
+			values = append(values, element.String())
+		} else if text := strings.TrimSpace(element.String()); !strings.EqualFold(text, "NULL") {
+			values = append(values, text)
+		}
+		element.Reset()
+		wasQuoted = false
+	}
+
+	for index := 0; index < len(runes); index++ {
+		character := runes[index]
+		switch {
+		case character == '\\':
+			index++
+			if index == len(runes) {
+				return nil, fmt.Errorf("delimited key %q ends with an escape character", value)
+			}
+			element.WriteRune(runes[index])
+
+		case character == '"':
+			quoted = !quoted
+			wasQuoted = true
+
+		case quoted:
+			element.WriteRune(character)
+
+		case character == '{' || character == '}':
+			return nil, fmt.Errorf("delimited key %q is a multidimensional array", value)
+
+		case character == delimiter:
+			appendElement()
+
+		default:
+			element.WriteRune(character)
+		}
+	}
+
+	if quoted {
+		return nil, fmt.Errorf("delimited key %q has an unterminated quote", value)
+	}
+	appendElement()
+
+	return values, nil
+}
@@ -0,0 +1,192 @@
+package configuration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelimitedKeySplitsText(t *testing.T) {
+	delimitedKey, err := NewDelimitedKey("", "", false)
+	assert.Nil(t, err)
+	assert.Equal(t, DelimitedKey{Format: TextDelimitedFormat, Delimiter: ","}, delimitedKey)
+
+	keys, err := delimitedKey.Split("1,2,,3")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1", "2", "3"}, keys)
+
+	keys, err = delimitedKey.Split("")
+	assert.Nil(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestDelimitedKeyTrimsText(t *testing.T) {
+	delimitedKey, err := NewDelimitedKey("TEXT", "|", true)
+	assert.Nil(t, err)
+
+	keys, err := delimitedKey.Split(" 1 | 2|  ")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1", "2"}, keys)
+}
+
+func TestDelimitedKeySplitsJSONArray(t *testing.T) {
+	delimitedKey, err := NewDelimitedKey("JSON", "", false)
+	assert.Nil(t, err)
+
+	keys, err := delimitedKey.Split(`[1, "2", null, 30000000000000001]`)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1", "2", "30000000000000001"}, keys)
+
+	_, err = delimitedKey.Split(`{"id": 1}`)
+	assert.NotNil(t, err)
+
+	_, err = delimitedKey.Split(`[[1]]`)
+	assert.NotNil(t, err)
+}
+
+func TestDelimitedKeySplitsPostgresArray(t *testing.T) {
+	delimitedKey, err := NewDelimitedKey("ARRAY", "", false)
+	assert.Nil(t, err)
+
+	keys, err := delimitedKey.Split(`{1, 2,NULL,"a,b","say \"hi\"",""}`)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1", "2", "a,b", `say "hi"`}, keys)
+
+	keys, err = delimitedKey.Split(`{}`)
+	assert.Nil(t, err)
+	assert.Empty(t, keys)
+
+	_, err = delimitedKey.Split(`{{1,2},{3,4}}`)
+	assert.NotNil(t, err)
+
+	_, err = delimitedKey.Split(`1,2`)
+	assert.NotNil(t, err)
+}
+
+func TestDelimitedKeyJoinsWhatItSplits(t *testing.T) {
+	textKey, err := NewDelimitedKey("TEXT", "|", true)
+	assert.Nil(t, err)
+	assert.Equal(t, "7|8", textKey.Join([]string{"7", "8"}))
+
+	jsonKey, err := NewDelimitedKey("JSON", "", false)
+	assert.Nil(t, err)
+	assert.Equal(t, `[7,"a\"b",-1.5]`, jsonKey.Join([]string{"7", `a"b`, "-1.5"}))
+
+	arrayKey, err := NewDelimitedKey("ARRAY", "", false)
+	assert.Nil(t, err)
+	joined := arrayKey.Join([]string{"7", "a,b", `say "hi"`, "NULL", ""})
+	assert.Equal(t, `{7,"a,b","say \"hi\"","NULL",""}`, joined)
+
+	keys, err := arrayKey.Split(joined)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"7", "a,b", `say "hi"`, "NULL"}, keys)
+}
+
+func TestDelimitedKeyDefaultsTheDelimiterOfStructLiterals(t *testing.T) {
+	arrayKey := DelimitedKey{Format: ArrayDelimitedFormat}
+	assert.Equal(t, ",", arrayKey.EffectiveDelimiter())
+
+	keys, err := arrayKey.Split(`{1,2}`)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1", "2"}, keys)
+	assert.Equal(t, `{1,2}`, arrayKey.Join(keys))
+
+	keys, err = DelimitedKey{}.Split("1,2")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1", "2"}, keys)
+}
+
+func TestNewDelimitedKeyRejectsInvalidOptions(t *testing.T) {
+	_, err := NewDelimitedKey("CSV", "", false)
+	var unknownDelimitedFormatError *UnknownDelimitedFormatError
+	assert.True(t, errors.As(err, &unknownDelimitedFormatError))
+
+	_, err = NewDelimitedKey("ARRAY", "::", false)
+	var invalidDelimiterError *InvalidDelimiterError
+	assert.True(t, errors.As(err, &invalidDelimiterError))
+
+	_, err = NewDelimitedKey("JSON", ",", false)
+	assert.True(t, errors.As(err, &invalidDelimiterError))
+}
+
+func TestRelationExpandsDelimitedFromKey(t *testing.T) {
+	configuration, err := NewConfigurationBuilderYml().Build(parseYmlSchema(t, `Name: Shop
+Resources:
+  Categories:
+    TableName: categories
+    PrimaryKey:
+      - categories.id
+  CustomerPreferences:
+    TableName: customer_preferences
+    ForeignKeys:
+      - Type: DELIMITED
+        Delimiter: ";"
+        Trim: true
+        Key: customer_preferences.fav_category_ids
+        ResourceName: Categories
+        ForeignKey: categories.id
+`))
+	assert.Nil(t, err)
+
+	relation := configuration.relationships.From("CustomerPreferences")["Categories"]
+	assert.Equal(t, DelimitedKeyType, relation.KeyType())
+	delimitedKey, isDelimited := relation.DelimitedKey()
+	assert.True(t, isDelimited)
+	assert.Equal(t, ";", delimitedKey.Delimiter)
+
+	keys, err := relation.ExpandFromKey("4; 8;15")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"4", "8", "15"}, keys)
+
+	relation = configuration.relationships.To("Categories")["CustomerPreferences::Categories"]
+	assert.Equal(t, DelimitedKeyType, relation.KeyType())
+}
+
+func TestValidateReportsInvalidKeyTypes(t *testing.T) {
+	ymlSchema := parseYmlSchema(t, `Name: Shop
+Resources:
+  Categories:
+    TableName: categories
+  CustomerPreferences:
+    TableName: customer_preferences
+    ForeignKeys:
+      - Name: Split
+        Type: SPLIT
+        Key: customer_preferences.category_ids
+        ResourceName: Categories
+        ForeignKey: categories.id
+      - Name: Composite
+        Type: DELIMITED
+        Key:
+          - customer_preferences.category_ids
+          - customer_preferences.tenant_id
+        ResourceName: Categories
+        ForeignKey:
+          - categories.id
+          - categories.tenant_id
+      - Name: Normal
+        Type: NORMAL
+        Delimiter: ","
+        Key: customer_preferences.category_id
+        ResourceName: Categories
+        ForeignKey: categories.id
+      - Name: Yaml
+        Type: DELIMITED
+        Format: YAML
+        Key: customer_preferences.category_ids
+        ResourceName: Categories
+        ForeignKey: categories.id
+`)
+
+	validationErrors := Validate(ymlSchema)
+
+	assert.Len(t, validationErrors, 4)
+	assert.Equal(t, "$.Resources.CustomerPreferences.ForeignKeys[0].Type", validationErrors[0].Path)
+	assert.Equal(t, 9, validationErrors[0].Line)
+	assert.Equal(t, "$.Resources.CustomerPreferences.ForeignKeys[1].Key", validationErrors[1].Path)
+	var delimitedKeyArityError *DelimitedKeyArityError
+	assert.True(t, errors.As(validationErrors[1], &delimitedKeyArityError))
+	assert.Equal(t, "$.Resources.CustomerPreferences.ForeignKeys[2].Delimiter", validationErrors[2].Path)
+	assert.Equal(t, "$.Resources.CustomerPreferences.ForeignKeys[3].Format", validationErrors[3].Path)
+}
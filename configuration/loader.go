@@ -0,0 +1,264 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+var configurationExtensions = []string{".yml", ".yaml"}
+
+type Loader struct {
+	parser Parser
+}
+
+func NewLoader() *Loader {
+	return NewLoaderWithParser(NewStrictYmlParser())
+}
+
+func NewLoaderWithParser(parser Parser) *Loader {
+	return &Loader{
+		parser: parser,
+	}
+}
+
+func LoadReader(reader io.Reader) (*Configuration, error) {
+	return NewLoader().LoadReader(reader)
+}
+
+func LoadFile(name string) (*Configuration, error) {
+	return NewLoader().LoadFile(name)
+}
+
+func LoadDir(dir string) ([]*Configuration, error) {
+	return NewLoader().LoadDir(dir)
+}
+
+func LoadFS(fsys fs.FS, name string) (*Configuration, error) {
+	return NewLoader().LoadFS(fsys, name)
+}
+
+func (loader *Loader) LoadReader(reader io.Reader) (*Configuration, error) {
+	ymlContent, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("reading configuration: %w", err)
+	}
+
+	ymlSchema, err := loader.parser.Parse(string(ymlContent))
+	if err != nil {
+		return nil, fmt.Errorf("parsing configuration: %w", err)
+	}
+
+	if len(ymlSchema.Include) > 0 {
+		return nil, errors.New("configuration read from an io.Reader cannot use Include, load it from a file or fs.FS instead")
+	}
+
+	return NewConfigurationBuilderYml().Build(ymlSchema)
+}
+
+func (loader *Loader) LoadFile(name string) (*Configuration, error) {
+	return loader.LoadFS(osDirFS(filepath.Dir(name)), filepath.Base(name))
+}
+
+func (loader *Loader) LoadDir(dir string) ([]*Configuration, error) {
+	return loader.loadFSDir(osDirFS(dir), ".")
+}
+
+func (loader *Loader) LoadFS(fsys fs.FS, name string) (*Configuration, error) {
+	ymlSchema, err := loader.newIncludeResolver(fsys).resolve(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return loader.build(name, ymlSchema)
+}
+
+func (loader *Loader) loadFSDir(fsys fs.FS, dir string) ([]*Configuration, error) {
+	dirEntries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading configuration directory %q: %w", dir, err)
+	}
+
+	var names []string
+	for _, dirEntry := range dirEntries {
+		if !dirEntry.IsDir() && isConfigurationFile(dirEntry.Name()) {
+			names = append(names, path.Join(dir, dirEntry.Name()))
+		}
+	}
+	sort.Strings(names)
+
+	ymlSchemas := make(map[string]YmlSchema)
+	includedNames := make(map[string]bool)
+	for _, name := range names {
+		includeResolver := loader.newIncludeResolver(fsys)
+		ymlSchema, err := includeResolver.resolve(name)
+		if err != nil {
+			return nil, err
+		}
+
+		ymlSchemas[name] = ymlSchema
+		for loadedName := range includeResolver.loaded {
+			if loadedName != name {
+				includedNames[loadedName] = true
+			}
+		}
+	}
+
+	var configurations []*Configuration
+	for _, name := range names {
+		if includedNames[name] {
+			continue
+		}
+
+		configuration, err := loader.build(name, ymlSchemas[name])
+		if err != nil {
+			return nil, err
+		}
+
+		configurations = append(configurations, configuration)
+	}
+
+	return configurations, nil
+}
+
+func (loader *Loader) build(name string, ymlSchema YmlSchema) (*Configuration, error) {
+	configuration, err := NewConfigurationBuilderYml().Build(ymlSchema)
+	if err != nil {
+		return nil, fmt.Errorf("loading configuration %q: %w", name, err)
+	}
+
+	return configuration, nil
+}
+
+func (loader *Loader) newIncludeResolver(fsys fs.FS) *includeResolver {
+	return &includeResolver{
+		fsys:   fsys,
+		parser: loader.parser,
+		loaded: make(map[string]bool),
+	}
+}
+
+type includeResolver struct {
+	fsys    fs.FS
+	parser  Parser
+	loading []string
+	loaded  map[string]bool
+}
+
+func (includeResolver *includeResolver) resolve(name string) (YmlSchema, error) {
+	for index, loadingName := range includeResolver.loading {
+		if loadingName == name {
+			files := append(append([]string{}, includeResolver.loading[index:]...), name)
+			return YmlSchema{}, &IncludeCycleError{Files: files}
+		}
+	}
+
+	if includeResolver.loaded[name] {
+		return YmlSchema{}, nil
+	}
+
+	ymlContent, err := fs.ReadFile(includeResolver.fsys, name)
+	if err != nil {
+		return YmlSchema{}, fmt.Errorf("reading configuration %q: %w", name, err)
+	}
+
+	ymlSchema, err := includeResolver.parser.Parse(string(ymlContent))
+	if validationErrors, isValidationErrors := err.(ValidationErrors); isValidationErrors {
+		for index := range validationErrors {
+			validationErrors[index].File = name
+		}
+	}
+	if err != nil {
+		return YmlSchema{}, fmt.Errorf("parsing configuration %q: %w", name, err)
+	}
+	ymlSchema.setSourceName(name)
+
+	includeResolver.loaded[name] = true
+	includeResolver.loading = append(includeResolver.loading, name)
+	defer func() {
+		includeResolver.loading = includeResolver.loading[:len(includeResolver.loading)-1]
+	}()
+
+	for _, pattern := range ymlSchema.Include {
+		includedNames, err := includeResolver.glob(path.Join(path.Dir(name), pattern))
+		if err != nil {
+			return YmlSchema{}, fmt.Errorf("resolving include %q in %q: %w", pattern, name, err)
+		}
+
+		for _, includedName := range includedNames {
+			included, err := includeResolver.resolve(includedName)
+			if err != nil {
+				return YmlSchema{}, err
+			}
+
+			if err := ymlSchema.merge(included); err != nil {
+				return YmlSchema{}, fmt.Errorf("including %q in %q: %w", includedName, name, err)
+			}
+		}
+	}
+
+	return ymlSchema, nil
+}
+
+func (includeResolver *includeResolver) glob(pattern string) ([]string, error) {
+	names, err := fs.Glob(includeResolver.fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no configuration matches %q: %w", pattern, fs.ErrNotExist)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func isConfigurationFile(name string) bool {
+	for _, extension := range configurationExtensions {
+		if path.Ext(name) == extension {
+			return true
+		}
+	}
+
+	return false
+}
+
+// osDirFS is os.DirFS without its restriction to names inside the directory,
+// so that included files may live in a parent directory.
+type osDirFS string
+
+func (dir osDirFS) Open(name string) (fs.File, error) {
+	return os.Open(dir.join(name))
+}
+
+func (dir osDirFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(dir.join(name))
+}
+
+func (dir osDirFS) Glob(pattern string) ([]string, error) {
+	matches, err := filepath.Glob(dir.join(pattern))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(matches))
+	for _, match := range matches {
+		name, err := filepath.Rel(string(dir), match)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, filepath.ToSlash(name))
+	}
+
+	return names, nil
+}
+
+func (dir osDirFS) join(name string) string {
+	return filepath.Join(string(dir), filepath.FromSlash(name))
+}
@@ -0,0 +1,315 @@
+package configuration
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const minimalYmlConfiguration = `Name: Minimal
+Description: Minimal configuration
+Resources:
+  MyTestResource:
+    TableName: my_test_table
+`
+
+func TestLoaderLoadsConfigurationFromReader(t *testing.T) {
+	configuration, err := LoadReader(strings.NewReader(minimalYmlConfiguration))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Minimal", configuration.name)
+	assert.Contains(t, configuration.resources, "MyTestResource")
+}
+
+func TestLoaderLoadsConfigurationFromEmbeddedFS(t *testing.T) {
+	configuration, err := LoadFS(testConfigurations, "configurations/entities_test.yml")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Example", configuration.name)
+	assert.Contains(t, configuration.entities, "MyTestEntity")
+}
+
+func TestLoaderLoadsConfigurationFromFile(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "minimal.yml")
+	assert.Nil(t, os.WriteFile(name, []byte(minimalYmlConfiguration), 0o644))
+
+	configuration, err := LoadFile(name)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Minimal", configuration.name)
+}
+
+func TestLoaderLoadsEveryConfigurationInDirectory(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "b.yaml"), []byte(minimalYmlConfiguration), 0o644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "a.yml"), []byte("Name: First\n"), 0o644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a configuration"), 0o644))
+
+	configurations, err := LoadDir(dir)
+
+	assert.Nil(t, err)
+	assert.Len(t, configurations, 2)
+	assert.Equal(t, "First", configurations[0].name)
+	assert.Equal(t, "Minimal", configurations[1].name)
+}
+
+func TestLoaderReportsMissingFile(t *testing.T) {
+	configuration, err := LoadFile(filepath.Join(t.TempDir(), "missing.yml"))
+
+	assert.Nil(t, configuration)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestLoaderReportsInvalidConfiguration(t *testing.T) {
+	configuration, err := LoadReader(strings.NewReader(`Name: Broken
+Entities:
+  MyTestEntity:
+    Components:
+      MyTestComponent1:
+        Elements:
+          ElementA:
+            Resource: MissingResource
+`))
+
+	assert.Nil(t, configuration)
+	var unknownResourceError *UnknownResourceError
+	assert.ErrorAs(t, err, &unknownResourceError)
+}
+
+func multiFileConfiguration() fstest.MapFS {
+	return fstest.MapFS{
+		"shop.yml": {Data: []byte(`Name: Shop
+Description: Shop split across files
+Include:
+  - resources/*.yml
+  - entities.yml
+`)},
+		"resources/customers.yml": {Data: []byte(`Resources:
+  Customers:
+    TableName: customers
+    PrimaryKey:
+      - customers.id
+`)},
+		"resources/orders.yml": {Data: []byte(`Resources:
+  Orders:
+    TableName: orders
+    PrimaryKey:
+      - orders.id
+    ForeignKeys:
+      - Type: NORMAL
+        Key: orders.customer_id
+        ResourceName: Customers
+        ForeignKey: customers.id
+`)},
+		"entities.yml": {Data: []byte(`Entities:
+  Orders:
+    Description: Orders of every customer
+    Components:
+      Orders:
+        Description: Orders component
+        Elements:
+          Customers:
+            Resource: Customers
+          Orders:
+            Resource: Orders
+            SelectionCriteria:
+              Type: Related
+              Elements:
+                - Customers
+`)},
+	}
+}
+
+func TestLoaderMergesIncludedFiles(t *testing.T) {
+	configuration, err := LoadFS(multiFileConfiguration(), "shop.yml")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Shop", configuration.name)
+	assert.Contains(t, configuration.resources, "Customers")
+	assert.Contains(t, configuration.resources, "Orders")
+	assert.Contains(t, configuration.relationships.from["Orders"].to, "Customers")
+	assert.Contains(t, configuration.entities, "Orders")
+}
+
+func TestLoaderReportsDuplicateResourcesAcrossFiles(t *testing.T) {
+	fsys := multiFileConfiguration()
+	fsys["resources/duplicate.yml"] = &fstest.MapFile{Data: []byte(`Resources:
+  Customers:
+    TableName: customers
+`)}
+
+	configuration, err := LoadFS(fsys, "shop.yml")
+
+	assert.Nil(t, configuration)
+	var duplicateResourceError *DuplicateResourceError
+	assert.ErrorAs(t, err, &duplicateResourceError)
+	assert.Equal(t, "Customers", duplicateResourceError.Resource)
+	assert.Equal(t, []string{"resources/customers.yml", "resources/duplicate.yml"}, duplicateResourceError.Files)
+}
+
+func TestLoaderReportsDuplicateEntitiesAcrossFiles(t *testing.T) {
+	fsys := multiFileConfiguration()
+	fsys["shop.yml"].Data = append(fsys["shop.yml"].Data, []byte(`Entities:
+  Orders:
+    Components:
+      Customers:
+        Elements:
+          Everyone:
+            Resource: Customers
+`)...)
+
+	configuration, err := LoadFS(fsys, "shop.yml")
+
+	assert.Nil(t, configuration)
+	var duplicateEntityError *DuplicateEntityError
+	assert.ErrorAs(t, err, &duplicateEntityError)
+	assert.Equal(t, []string{"shop.yml", "entities.yml"}, duplicateEntityError.Files)
+}
+
+func TestLoaderMergesComponentsOfAnEntitySplitAcrossFiles(t *testing.T) {
+	fsys := multiFileConfiguration()
+	fsys["shop.yml"].Data = append(fsys["shop.yml"].Data, []byte("  - customers.yml\n")...)
+	fsys["customers.yml"] = &fstest.MapFile{Data: []byte(`Entities:
+  Orders:
+    Extend: true
+    Components:
+      Customers:
+        Description: Customers component
+        Elements:
+          Everyone:
+            Resource: Customers
+`)}
+
+	configuration, err := LoadFS(fsys, "shop.yml")
+
+	assert.Nil(t, err)
+	entity := configuration.entities["Orders"]
+	assert.Equal(t, "Orders of every customer", entity.Description())
+	assert.Equal(t, []string{"Customers", "Orders"}, entity.ComponentNames())
+}
+
+func TestLoaderReportsDuplicateComponentsAcrossFiles(t *testing.T) {
+	fsys := multiFileConfiguration()
+	fsys["shop.yml"].Data = append(fsys["shop.yml"].Data, []byte(`Entities:
+  Orders:
+    Extend: true
+    Components:
+      Orders:
+        Elements:
+          Customers:
+            Resource: Customers
+`)...)
+
+	configuration, err := LoadFS(fsys, "shop.yml")
+
+	assert.Nil(t, configuration)
+	var duplicateComponentError *DuplicateComponentError
+	assert.ErrorAs(t, err, &duplicateComponentError)
+	assert.Equal(t, "Orders", duplicateComponentError.Entity)
+	assert.Equal(t, "Orders", duplicateComponentError.Component)
+	assert.Equal(t, []string{"shop.yml", "entities.yml"}, duplicateComponentError.Files)
+}
+
+func TestLoaderReportsValidationErrorsInComponentFromAnotherFile(t *testing.T) {
+	fsys := multiFileConfiguration()
+	fsys["shop.yml"].Data = append(fsys["shop.yml"].Data, []byte(`Entities:
+  Orders:
+    Extend: true
+    Components:
+      Customers:
+        Elements:
+          Everyone:
+            Resource: Missing
+`)...)
+
+	configuration, err := LoadFS(fsys, "shop.yml")
+
+	assert.Nil(t, configuration)
+	var validationErrors ValidationErrors
+	assert.ErrorAs(t, err, &validationErrors)
+	assert.Len(t, validationErrors, 1)
+	assert.Equal(t, "shop.yml", validationErrors[0].File)
+	assert.Equal(t, 13, validationErrors[0].Line)
+}
+
+func TestLoaderReportsValidationErrorsInIncludedFile(t *testing.T) {
+	fsys := multiFileConfiguration()
+	delete(fsys, "resources/customers.yml")
+
+	configuration, err := LoadFS(fsys, "shop.yml")
+
+	assert.Nil(t, configuration)
+	var validationErrors ValidationErrors
+	assert.ErrorAs(t, err, &validationErrors)
+	assert.Len(t, validationErrors, 2)
+	assert.Equal(t, "resources/orders.yml", validationErrors[0].File)
+	assert.Equal(t, 9, validationErrors[0].Line)
+	assert.Contains(t, validationErrors[0].Error(), "resources/orders.yml:9:23:")
+	assert.Equal(t, "entities.yml", validationErrors[1].File)
+	assert.Equal(t, 9, validationErrors[1].Line)
+}
+
+func TestLoaderReportsIncludeCycles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.yml": {Data: []byte("Name: A\nInclude:\n  - b.yml\n")},
+		"b.yml": {Data: []byte("Include:\n  - a.yml\n")},
+	}
+
+	configuration, err := LoadFS(fsys, "a.yml")
+
+	assert.Nil(t, configuration)
+	var includeCycleError *IncludeCycleError
+	assert.ErrorAs(t, err, &includeCycleError)
+	assert.Equal(t, []string{"a.yml", "b.yml", "a.yml"}, includeCycleError.Files)
+}
+
+func TestLoaderReportsMissingInclude(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.yml": {Data: []byte("Name: A\nInclude:\n  - missing.yml\n")},
+	}
+
+	configuration, err := LoadFS(fsys, "a.yml")
+
+	assert.Nil(t, configuration)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestLoaderSkipsIncludedFilesWhenLoadingDirectory(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "shop.yml"), []byte("Name: Shop\nInclude:\n  - resources.yml\n"), 0o644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "resources.yml"), []byte(minimalYmlConfiguration), 0o644))
+
+	configurations, err := LoadDir(dir)
+
+	assert.Nil(t, err)
+	assert.Len(t, configurations, 1)
+	assert.Equal(t, "Shop", configurations[0].name)
+	assert.Contains(t, configurations[0].resources, "MyTestResource")
+}
+
+func TestLoaderIncludesFilesFromParentDirectories(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "common"), 0o755))
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "shop"), 0o755))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "common", "resources.yml"), []byte(minimalYmlConfiguration), 0o644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "shop", "shop.yml"), []byte("Name: Shop\nInclude:\n  - ../common/*.yml\n"), 0o644))
+
+	configuration, err := LoadFile(filepath.Join(dir, "shop", "shop.yml"))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Shop", configuration.name)
+	assert.Contains(t, configuration.resources, "MyTestResource")
+}
+
+func TestLoaderRejectsIncludesFromReader(t *testing.T) {
+	configuration, err := LoadReader(strings.NewReader("Name: A\nInclude:\n  - b.yml\n"))
+
+	assert.Nil(t, configuration)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,206 @@
+package configuration
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+type ParameterType string
+
+const (
+	StringParameterType  ParameterType = "String"
+	IntegerParameterType ParameterType = "Integer"
+	NumberParameterType  ParameterType = "Number"
+	BooleanParameterType ParameterType = "Boolean"
+)
+
+func isKnownParameterType(parameterType ParameterType) bool {
+	switch parameterType {
+	case StringParameterType, IntegerParameterType, NumberParameterType, BooleanParameterType:
+		return true
+	}
+
+	return false
+}
+
+type Parameter struct {
+	name          string
+	parameterType ParameterType
+	required      bool
+	defaultValue  any
+}
+
+func NewParameter(name string, ymlParameter YmlParameter) (*Parameter, error) {
+	parameter := &Parameter{
+		name:          name,
+		parameterType: ParameterType(ymlParameter.Type),
+		required:      ymlParameter.Required,
+	}
+
+	if parameter.parameterType == "" {
+		parameter.parameterType = StringParameterType
+	}
+	if !isKnownParameterType(parameter.parameterType) {
+		return nil, &UnknownParameterTypeError{Parameter: name, Type: ymlParameter.Type}
+	}
+
+	if ymlParameter.Default != nil {
+		defaultValue, err := parameter.Convert(ymlParameter.Default)
+		if err != nil {
+			return nil, err
+		}
+
+		parameter.defaultValue = defaultValue
+	}
+
+	return parameter, nil
+}
+
+func (parameter Parameter) Name() string {
+	return parameter.name
+}
+
+func (parameter Parameter) Type() ParameterType {
+	return parameter.parameterType
+}
+
+func (parameter Parameter) Required() bool {
+	return parameter.required
+}
+
+func (parameter Parameter) Default() (any, bool) {
+	return parameter.defaultValue, parameter.defaultValue != nil
+}
+
+func (parameter Parameter) Convert(value any) (any, error) {
+	converted, err := convertParameterValue(parameter.parameterType, value)
+	if err != nil {
+		return nil, &InvalidParameterValueError{
+			Parameter: parameter.name,
+			Type:      parameter.parameterType,
+			Value:     value,
+			Err:       err,
+		}
+	}
+
+	return converted, nil
+}
+
+func convertParameterValue(parameterType ParameterType, value any) (any, error) {
+	switch parameterType {
+	case StringParameterType:
+		switch value := value.(type) {
+		case string:
+			return value, nil
+		case fmt.Stringer:
+			return value.String(), nil
+		case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			return fmt.Sprint(value), nil
+		}
+
+	case IntegerParameterType:
+		switch value := value.(type) {
+		case int:
+			return int64(value), nil
+		case int8:
+			return int64(value), nil
+		case int16:
+			return int64(value), nil
+		case int32:
+			return int64(value), nil
+		case int64:
+			return value, nil
+		case uint:
+			return convertUnsigned(uint64(value))
+		case uint8:
+			return int64(value), nil
+		case uint16:
+			return int64(value), nil
+		case uint32:
+			return int64(value), nil
+		case uint64:
+			return convertUnsigned(value)
+		case float64:
+			if value != math.Trunc(value) || math.Abs(value) > math.MaxInt64 {
+				return nil, fmt.Errorf("%v is not an integer", value)
+			}
+			return int64(value), nil
+		case string:
+			return strconv.ParseInt(value, 10, 64)
+		}
+
+	case NumberParameterType:
+		switch value := value.(type) {
+		case float64:
+			return value, nil
+		case float32:
+			return float64(value), nil
+		case string:
+			return strconv.ParseFloat(value, 64)
+		default:
+			integer, err := convertParameterValue(IntegerParameterType, value)
+			if err != nil {
+				return nil, err
+			}
+			return float64(integer.(int64)), nil
+		}
+
+	case BooleanParameterType:
+		switch value := value.(type) {
+		case bool:
+			return value, nil
+		case string:
+			return strconv.ParseBool(value)
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported value of type %T", value)
+}
+
+func convertUnsigned(value uint64) (any, error) {
+	if value > math.MaxInt64 {
+		return nil, fmt.Errorf("%d overflows a 64-bit integer", value)
+	}
+
+	return int64(value), nil
+}
+
+type Parameters map[string]Parameter
+
+func (parameters Parameters) Names() []string {
+	return sortedKeys(parameters)
+}
+
+func (parameters Parameters) Bind(values map[string]any) (map[string]any, error) {
+	for _, name := range sortedKeys(values) {
+		if _, exists := parameters[name]; !exists {
+			return nil, &UnknownParameterError{Parameter: name}
+		}
+	}
+
+	bound := make(map[string]any, len(parameters))
+	for _, name := range parameters.Names() {
+		parameter := parameters[name]
+		value, exists := values[name]
+		if !exists || value == nil {
+			if defaultValue, hasDefault := parameter.Default(); hasDefault {
+				bound[name] = defaultValue
+				continue
+			}
+			if parameter.required {
+				return nil, &MissingParameterError{Parameter: name}
+			}
+			continue
+		}
+
+		converted, err := parameter.Convert(value)
+		if err != nil {
+			return nil, err
+		}
+
+		bound[name] = converted
+	}
+
+	return bound, nil
+}
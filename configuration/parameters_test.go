@@ -0,0 +1,128 @@
+package configuration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const parametersYmlConfiguration = `Name: Shop
+Resources:
+  Customers:
+    TableName: customers
+Entities:
+  CoreProduct:
+    Parameters:
+      region:
+        Required: true
+      limit:
+        Type: Integer
+        Default: 100
+      active:
+        Type: Boolean
+    Components:
+      Customers:
+        Elements:
+          Customers:
+            Resource: Customers
+            SelectionCriteria:
+              Type: Custom
+              Criteria: region = "{{region}}" AND id < {{limit}}
+`
+
+func TestEntityBindsParameters(t *testing.T) {
+	configuration, err := NewConfigurationBuilderYml().Build(parseYmlSchema(t, parametersYmlConfiguration))
+	assert.Nil(t, err)
+
+	entity, _ := configuration.Entity("CoreProduct")
+	assert.Equal(t, []string{"active", "limit", "region"}, entity.Parameters().Names())
+	limit := entity.Parameters()["limit"]
+	assert.Equal(t, IntegerParameterType, limit.Type())
+	defaultLimit, hasDefault := limit.Default()
+	assert.True(t, hasDefault)
+	assert.Equal(t, int64(100), defaultLimit)
+
+	values, err := entity.BindParameters(map[string]any{"region": "EU", "active": "true"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]any{"region": "EU", "limit": int64(100), "active": true}, values)
+
+	values, err = entity.BindParameters(map[string]any{"region": "EU", "limit": "25"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]any{"region": "EU", "limit": int64(25)}, values)
+
+	customers, _ := configuration.Element(ElementPath{Entity: "CoreProduct", Component: "Customers", Element: "Customers"})
+	customSelectionCriteria := customers.SelectionCriteria().(*CustomSelectionCriteria)
+	sql, args, err := customSelectionCriteria.Template().Render(values, questionMark)
+	assert.Nil(t, err)
+	assert.Equal(t, "region = ? AND id < ?", sql)
+	assert.Equal(t, []any{"EU", int64(25)}, args)
+}
+
+func TestEntityRejectsInvalidParameterValues(t *testing.T) {
+	configuration, err := NewConfigurationBuilderYml().Build(parseYmlSchema(t, parametersYmlConfiguration))
+	assert.Nil(t, err)
+	entity, _ := configuration.Entity("CoreProduct")
+
+	_, err = entity.BindParameters(map[string]any{})
+	var missingParameterError *MissingParameterError
+	assert.True(t, errors.As(err, &missingParameterError))
+	assert.Equal(t, "region", missingParameterError.Parameter)
+
+	_, err = entity.BindParameters(map[string]any{"region": "EU", "country": "DE"})
+	var unknownParameterError *UnknownParameterError
+	assert.True(t, errors.As(err, &unknownParameterError))
+	assert.Equal(t, "country", unknownParameterError.Parameter)
+
+	_, err = entity.BindParameters(map[string]any{"region": "EU", "limit": "ten"})
+	var invalidParameterValueError *InvalidParameterValueError
+	assert.True(t, errors.As(err, &invalidParameterValueError))
+	assert.Equal(t, "limit", invalidParameterValueError.Parameter)
+
+	_, err = entity.BindParameters(map[string]any{"region": "EU", "limit": 2.5})
+	assert.True(t, errors.As(err, &invalidParameterValueError))
+}
+
+func TestValidateReportsParameterErrors(t *testing.T) {
+	ymlSchema := parseYmlSchema(t, `Name: Shop
+Resources:
+  Customers:
+    TableName: customers
+Entities:
+  CoreProduct:
+    Parameters:
+      region:
+        Type: Text
+      limit:
+        Type: Integer
+        Default: many
+    Components:
+      Customers:
+        Elements:
+          Customers:
+            Resource: Customers
+            SelectionCriteria:
+              Type: Custom
+              Criteria: country = '{{country}}'
+          Orders:
+            Resource: Customers
+            SelectionCriteria:
+              Type: Custom
+              Criteria: name LIKE '{{country}}%'
+`)
+
+	validationErrors := Validate(ymlSchema)
+
+	assert.Len(t, validationErrors, 4)
+	assert.Equal(t, "$.Entities.CoreProduct.Parameters.limit.Default", validationErrors[0].Path)
+	assert.Equal(t, 12, validationErrors[0].Line)
+	assert.Equal(t, "$.Entities.CoreProduct.Parameters.region.Type", validationErrors[1].Path)
+	var unknownParameterTypeError *UnknownParameterTypeError
+	assert.True(t, errors.As(validationErrors[1], &unknownParameterTypeError))
+	assert.Equal(t, "$.Entities.CoreProduct.Components.Customers.Elements.Customers.SelectionCriteria.Criteria", validationErrors[2].Path)
+	var unknownParameterError *UnknownParameterError
+	assert.True(t, errors.As(validationErrors[2], &unknownParameterError))
+	assert.Equal(t, "country", unknownParameterError.Parameter)
+	assert.Equal(t, "$.Entities.CoreProduct.Components.Customers.Elements.Orders.SelectionCriteria.Criteria", validationErrors[3].Path)
+	assert.Contains(t, validationErrors[3].Message, "whole string literal")
+}
@@ -0,0 +1,52 @@
+package configuration
+
+type RecursionDirection string
+
+const (
+	AncestorsRecursion   RecursionDirection = "Ancestors"
+	DescendantsRecursion RecursionDirection = "Descendants"
+	BothRecursion        RecursionDirection = "Both"
+)
+
+func isKnownRecursionDirection(direction RecursionDirection) bool {
+	switch direction {
+	case AncestorsRecursion, DescendantsRecursion, BothRecursion:
+		return true
+	}
+
+	return false
+}
+
+type Recursion struct {
+	direction RecursionDirection
+	relation  Relation
+	maxDepth  int
+}
+
+func NewRecursion(direction RecursionDirection, relation Relation, maxDepth int) *Recursion {
+	return &Recursion{
+		direction: direction,
+		relation:  relation,
+		maxDepth:  maxDepth,
+	}
+}
+
+func (recursion Recursion) Direction() RecursionDirection {
+	return recursion.direction
+}
+
+func (recursion Recursion) Relation() Relation {
+	return recursion.relation
+}
+
+func (recursion Recursion) MaxDepth() int {
+	return recursion.maxDepth
+}
+
+func (recursion Recursion) Directions() []RecursionDirection {
+	if recursion.direction == BothRecursion {
+		return []RecursionDirection{AncestorsRecursion, DescendantsRecursion}
+	}
+
+	return []RecursionDirection{recursion.direction}
+}
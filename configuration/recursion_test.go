@@ -0,0 +1,93 @@
+package configuration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const categoriesYmlConfiguration = `Name: Shop
+Resources:
+  Categories:
+    TableName: categories
+    PrimaryKey:
+      - categories.id
+    ForeignKeys:
+      - Name: Parent
+        Type: NORMAL
+        Key: categories.parent_id
+        ResourceName: Categories
+        ForeignKey: categories.id
+  Products:
+    TableName: products
+    PrimaryKey:
+      - products.id
+    ForeignKeys:
+      - Type: NORMAL
+        Key: products.category_id
+        ResourceName: Categories
+        ForeignKey: categories.id
+Entities:
+  CoreProduct:
+    Components:
+      Products:
+        Elements:
+          Products:
+            Resource: Products
+            SelectionCriteria:
+              Type: Custom
+              Criteria: products.id = 1
+          Categories:
+            Resource: Categories
+            SelectionCriteria:
+              Type: Related
+              Via: Categories
+              Elements:
+                - Products
+              Recursive:
+                Direction: Both
+                MaxDepth: 2
+`
+
+func TestConfigurationBuilderBuildsRecursiveSelection(t *testing.T) {
+	configuration, err := NewConfigurationBuilderYml().Build(parseYmlSchema(t, categoriesYmlConfiguration))
+	assert.Nil(t, err)
+
+	categories, _ := configuration.Element(ElementPath{Entity: "CoreProduct", Component: "Products", Element: "Categories"})
+	recursion, isRecursive := categories.Recursion()
+	assert.True(t, isRecursive)
+	assert.Equal(t, BothRecursion, recursion.Direction())
+	assert.Equal(t, 2, recursion.MaxDepth())
+	assert.Equal(t, "Categories::Parent", recursion.Relation().QualifiedName())
+
+	products, _ := configuration.Element(ElementPath{Entity: "CoreProduct", Component: "Products", Element: "Products"})
+	_, isRecursive = products.Recursion()
+	assert.False(t, isRecursive)
+}
+
+func TestValidateReportsInvalidRecursion(t *testing.T) {
+	ymlSchema := parseYmlSchema(t, categoriesYmlConfiguration+`          Products2:
+            Resource: Products
+            SelectionCriteria:
+              Type: Related
+              Via: Categories
+              Elements:
+                - Categories
+              Recursive:
+                Direction: Upwards
+                MaxDepth: -1
+`)
+
+	validationErrors := Validate(ymlSchema)
+
+	assert.Len(t, validationErrors, 3)
+	assert.Equal(t, "$.Entities.CoreProduct.Components.Products.Elements.Products2.SelectionCriteria.Recursive.Direction", validationErrors[0].Path)
+	var unknownRecursionDirectionError *UnknownRecursionDirectionError
+	assert.True(t, errors.As(validationErrors[0], &unknownRecursionDirectionError))
+	assert.Equal(t, "$.Entities.CoreProduct.Components.Products.Elements.Products2.SelectionCriteria.Recursive.MaxDepth", validationErrors[1].Path)
+	assert.Equal(t, "$.Entities.CoreProduct.Components.Products.Elements.Products2.SelectionCriteria.Recursive", validationErrors[2].Path)
+	var noRelationError *NoRelationError
+	assert.True(t, errors.As(validationErrors[2], &noRelationError))
+	assert.Equal(t, "Products", noRelationError.RelatedResource)
+}
@@ -0,0 +1,202 @@
+package configuration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const addressesYmlConfiguration = `Name: Shop
+Resources:
+  Addresses:
+    TableName: addresses
+    PrimaryKey:
+      - addresses.id
+  Orders:
+    TableName: orders
+    PrimaryKey:
+      - orders.id
+    ForeignKeys:
+      - Name: BillingAddress
+        Type: NORMAL
+        Key: orders.billing_address_id
+        ResourceName: Addresses
+        ForeignKey: addresses.id
+      - Name: ShippingAddress
+        Type: NORMAL
+        Key: orders.shipping_address_id
+        ResourceName: Addresses
+        ForeignKey: addresses.id
+Entities:
+  Sales:
+    Components:
+      Orders:
+        Elements:
+          Orders:
+            Resource: Orders
+            SelectionCriteria:
+              Type: Custom
+              Criteria: 1 = 1
+          ShippingAddresses:
+            Resource: Addresses
+            SelectionCriteria:
+              Type: Related
+              Via: ShippingAddress
+              Elements:
+                - Orders
+`
+
+func TestConfigurationBuilderKeepsEveryForeignKeyBetweenTwoResources(t *testing.T) {
+	configuration, err := NewConfigurationBuilderYml().Build(parseYmlSchema(t, addressesYmlConfiguration))
+	assert.Nil(t, err)
+
+	from := configuration.relationships.From("Orders")
+	assert.Equal(t, []string{"BillingAddress", "ShippingAddress"}, from.Names())
+	assert.Equal(t, "orders", from["BillingAddress"].FromTable())
+	assert.Equal(t, "billing_address_id", from["BillingAddress"].fromKeys[0].Column)
+	assert.Equal(t, "shipping_address_id", from["ShippingAddress"].fromKeys[0].Column)
+
+	to := configuration.relationships.To("Addresses")
+	assert.Equal(t, []string{"Orders::BillingAddress", "Orders::ShippingAddress"}, to.Names())
+	assert.Equal(t, "Addresses", to["Orders::ShippingAddress"].ToResource())
+	assert.Equal(t, "Orders", to["Orders::ShippingAddress"].FromResource())
+
+	between := configuration.relationships.Between("Addresses", "Orders")
+	assert.Len(t, between, 2)
+	assert.Equal(t, "BillingAddress", between[0].Name())
+	assert.Equal(t, "ShippingAddress", between[1].Name())
+
+	shippingAddresses, _ := configuration.Element(ElementPath{Entity: "Sales", Component: "Orders", Element: "ShippingAddresses"})
+	relatedSelectionCriteria := shippingAddresses.selectionCriteria.(*RelatedSelectionCriteria)
+	assert.Equal(t, "ShippingAddress", relatedSelectionCriteria.Via())
+	assert.Len(t, relatedSelectionCriteria.Relations(), 1)
+	assert.Equal(t, "Orders::ShippingAddress", relatedSelectionCriteria.Relations()[0].QualifiedName())
+}
+
+func TestForeignKeyNameDefaultsToForeignResource(t *testing.T) {
+	configuration, err := NewConfigurationBuilderYml().Build(getYmlSchema())
+	assert.Nil(t, err)
+
+	foreignKeys := configuration.resources["MyTestResource"].ForeignKeys()
+	assert.Equal(t, "MyTestResource2", foreignKeys[0].Name())
+}
+
+func TestValidateReportsDuplicateForeignKeyNames(t *testing.T) {
+	ymlSchema := parseYmlSchema(t, `Name: Shop
+Resources:
+  Addresses:
+    TableName: addresses
+  Orders:
+    TableName: orders
+    ForeignKeys:
+      - Type: NORMAL
+        Key: orders.billing_address_id
+        ResourceName: Addresses
+        ForeignKey: addresses.id
+      - Type: NORMAL
+        Key: orders.shipping_address_id
+        ResourceName: Addresses
+        ForeignKey: addresses.id
+`)
+
+	validationErrors := Validate(ymlSchema)
+
+	assert.Len(t, validationErrors, 1)
+	assert.Equal(t, "$.Resources.Orders.ForeignKeys[1]", validationErrors[0].Path)
+	var duplicateForeignKeyError *DuplicateForeignKeyError
+	assert.True(t, errors.As(validationErrors[0], &duplicateForeignKeyError))
+	assert.Equal(t, "Addresses", duplicateForeignKeyError.ForeignKey)
+}
+
+func TestValidateRequiresViaWhenSeveralForeignKeysRelateElements(t *testing.T) {
+	ymlSchema := parseYmlSchema(t, addressesYmlConfiguration+`          Addresses:
+            Resource: Addresses
+            SelectionCriteria:
+              Type: Related
+              Elements:
+                - Orders
+`)
+
+	validationErrors := Validate(ymlSchema)
+
+	assert.Len(t, validationErrors, 1)
+	assert.Equal(t, "$.Entities.Sales.Components.Orders.Elements.Addresses.SelectionCriteria.Elements[0]", validationErrors[0].Path)
+	var ambiguousRelationError *AmbiguousRelationError
+	assert.True(t, errors.As(validationErrors[0], &ambiguousRelationError))
+	assert.Equal(t, []string{"Orders::BillingAddress", "Orders::ShippingAddress"}, ambiguousRelationError.Relations)
+}
+
+func TestValidateReportsRelatedElementsWithoutForeignKey(t *testing.T) {
+	ymlSchema := parseYmlSchema(t, addressesYmlConfiguration+`          MoreOrders:
+            Resource: Orders
+            SelectionCriteria:
+              Type: Related
+              Elements:
+                - Orders
+          BillingAddresses:
+            Resource: Addresses
+            SelectionCriteria:
+              Type: Related
+              Via: InvoiceAddress
+              Elements:
+                - Orders
+`)
+
+	validationErrors := Validate(ymlSchema)
+
+	assert.Len(t, validationErrors, 2)
+	var noRelationError *NoRelationError
+	assert.True(t, errors.As(validationErrors[0], &noRelationError))
+	assert.Equal(t, "InvoiceAddress", noRelationError.Via)
+	assert.True(t, errors.As(validationErrors[1], &noRelationError))
+	assert.Equal(t, "Orders", noRelationError.Resource)
+	assert.Equal(t, "Orders", noRelationError.RelatedResource)
+}
+
+func TestRelationshipsOrderResourcesAfterTheResourcesTheyReference(t *testing.T) {
+	configuration, err := NewConfigurationBuilderYml().Build(parseYmlSchema(t, addressesYmlConfiguration))
+	assert.Nil(t, err)
+
+	order, err := configuration.Relationships().DependencyOrder([]string{"Orders", "Addresses"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Addresses", "Orders"}, order)
+
+	order, err = configuration.Relationships().DependencyOrder([]string{"Orders"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Orders"}, order)
+}
+
+func TestRelationshipsDeferOrReportCyclicResourceDependencies(t *testing.T) {
+	configuration, err := NewConfigurationBuilderYml().Build(parseYmlSchema(t, `Name: Shop
+Resources:
+  Customers:
+    TableName: customers
+    ForeignKeys:
+      - Name: DefaultAddress
+        Type: NORMAL
+        Key: customers.default_address_id
+        ResourceName: Addresses
+        ForeignKey: addresses.id
+  Addresses:
+    TableName: addresses
+    ForeignKeys:
+      - Type: NORMAL
+        Key: addresses.customer_id
+        ResourceName: Customers
+        ForeignKey: customers.id
+Entities: {}
+`))
+	assert.Nil(t, err)
+
+	order, deferred := configuration.Relationships().DeferredDependencyOrder([]string{"Customers", "Addresses"})
+	assert.Equal(t, []string{"Customers", "Addresses"}, order)
+	assert.Len(t, deferred, 1)
+	assert.Equal(t, "Customers::DefaultAddress", deferred[0].QualifiedName())
+
+	_, err = configuration.Relationships().DependencyOrder([]string{"Customers", "Addresses"})
+	var cyclicResourceDependencyError *CyclicResourceDependencyError
+	assert.True(t, errors.As(err, &cyclicResourceDependencyError))
+	assert.Equal(t, []string{"Addresses", "Customers", "Addresses"}, cyclicResourceDependencyError.Resources)
+	assert.Equal(t, "cyclic resource dependency: Addresses -> Customers -> Addresses", err.Error())
+}
@@ -0,0 +1,216 @@
+package configuration
+
+import (
+	"sort"
+	"sync"
+)
+
+type SelectionCriteriaDefinition struct {
+	Type          string
+	Criteria      string
+	Index         string
+	Via           string
+	Direction     string
+	Options       map[string]any
+	Element       *Element
+	Elements      []*Element
+	Relationships Relationships
+}
+
+type SelectionCriteriaFactory func(definition SelectionCriteriaDefinition) (SelectionCriteria, error)
+
+type selectionCriteriaRegistration struct {
+	factory        SelectionCriteriaFactory
+	acceptsOptions bool
+}
+
+var (
+	selectionCriteriaFactoriesMutex sync.RWMutex
+	selectionCriteriaFactories      = make(map[string]selectionCriteriaRegistration)
+)
+
+func init() {
+	registerSelectionCriteria("Custom", newCustomSelectionCriteria, false)
+	registerSelectionCriteria("Index", newIndexedSelectionCriteria, false)
+	registerSelectionCriteria("Related", newRelatedSelectionCriteria, false)
+}
+
+func RegisterSelectionCriteria(selectionCriteriaType string, factory SelectionCriteriaFactory) {
+	registerSelectionCriteria(selectionCriteriaType, factory, true)
+}
+
+func registerSelectionCriteria(selectionCriteriaType string, factory SelectionCriteriaFactory, acceptsOptions bool) {
+	selectionCriteriaFactoriesMutex.Lock()
+	defer selectionCriteriaFactoriesMutex.Unlock()
+
+	if selectionCriteriaType == "" {
+		panic("configuration: RegisterSelectionCriteria type is empty")
+	}
+	if factory == nil {
+		panic("configuration: RegisterSelectionCriteria factory is nil for type " + selectionCriteriaType)
+	}
+	if _, exists := selectionCriteriaFactories[selectionCriteriaType]; exists {
+		panic("configuration: RegisterSelectionCriteria called twice for type " + selectionCriteriaType)
+	}
+
+	selectionCriteriaFactories[selectionCriteriaType] = selectionCriteriaRegistration{
+		factory:        factory,
+		acceptsOptions: acceptsOptions,
+	}
+}
+
+func SelectionCriteriaTypes() []string {
+	selectionCriteriaFactoriesMutex.RLock()
+	defer selectionCriteriaFactoriesMutex.RUnlock()
+
+	selectionCriteriaTypes := make([]string, 0, len(selectionCriteriaFactories))
+	for selectionCriteriaType := range selectionCriteriaFactories {
+		selectionCriteriaTypes = append(selectionCriteriaTypes, selectionCriteriaType)
+	}
+	sort.Strings(selectionCriteriaTypes)
+
+	return selectionCriteriaTypes
+}
+
+func lookupSelectionCriteriaFactory(selectionCriteriaType string) (SelectionCriteriaFactory, bool) {
+	selectionCriteriaFactoriesMutex.RLock()
+	defer selectionCriteriaFactoriesMutex.RUnlock()
+
+	registration, exists := selectionCriteriaFactories[selectionCriteriaType]
+
+	return registration.factory, exists
+}
+
+func selectionCriteriaAcceptsOptions(selectionCriteriaType string) bool {
+	selectionCriteriaFactoriesMutex.RLock()
+	defer selectionCriteriaFactoriesMutex.RUnlock()
+
+	return selectionCriteriaFactories[selectionCriteriaType].acceptsOptions
+}
+
+func newCustomSelectionCriteria(definition SelectionCriteriaDefinition) (SelectionCriteria, error) {
+	template, err := ParseCriteriaTemplate(definition.Criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	customSelectionCriteria := NewCustomSelectionCriteria()
+	customSelectionCriteria.criteria = definition.Criteria
+	customSelectionCriteria.template = template
+
+	return customSelectionCriteria, nil
+}
+
+func newIndexedSelectionCriteria(definition SelectionCriteriaDefinition) (SelectionCriteria, error) {
+	resource := definition.Element.resource
+	columns, exists := resource.Index(definition.Index)
+	if !exists {
+		return nil, &UnknownIndexError{Resource: resource.name, Index: definition.Index}
+	}
+
+	indexedSelectionCriteria := NewIndexedSelectionCriteria()
+	indexedSelectionCriteria.elements = definition.Elements
+	indexedSelectionCriteria.index = definition.Index
+	indexedSelectionCriteria.columns = columns
+	for _, upstream := range definition.Elements {
+		upstreamColumns := upstream.resource.PrimaryKey()
+		if len(upstreamColumns) != len(columns) {
+			return nil, &IndexArityError{
+				Resource:         resource.name,
+				Index:            definition.Index,
+				Columns:          len(columns),
+				Upstream:         upstream.Name(),
+				UpstreamResource: upstream.resource.name,
+				UpstreamColumns:  len(upstreamColumns),
+			}
+		}
+
+		indexedSelectionCriteria.mappings = append(indexedSelectionCriteria.mappings, IndexMapping{
+			Upstream:        upstream,
+			Columns:         columns,
+			UpstreamColumns: upstreamColumns,
+		})
+	}
+
+	return indexedSelectionCriteria, nil
+}
+
+func newRelatedSelectionCriteria(definition SelectionCriteriaDefinition) (SelectionCriteria, error) {
+	relatedSelectionCriteria := NewRelatedSelectionCriteria()
+	relatedSelectionCriteria.elements = definition.Elements
+	relatedSelectionCriteria.via = definition.Via
+	for _, upstream := range definition.Elements {
+		relation, err := relationVia(
+			definition.Relationships,
+			definition.Element.resource.name,
+			upstream.resource.name,
+			definition.Via,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		direction, err := relationDirection(relation, definition.Element.resource.name, RelationDirection(definition.Direction))
+		if err != nil {
+			return nil, err
+		}
+
+		relatedSelectionCriteria.relations = append(relatedSelectionCriteria.relations, relation)
+		relatedSelectionCriteria.directions = append(relatedSelectionCriteria.directions, direction)
+	}
+
+	return relatedSelectionCriteria, nil
+}
+
+func relationDirection(relation Relation, resourceName string, direction RelationDirection) (RelationDirection, error) {
+	switch direction {
+	case "":
+		if relation.fromResource == resourceName {
+			return ReferencingDirection, nil
+		}
+		return ReferencedDirection, nil
+
+	case ReferencingDirection:
+		if relation.fromResource == resourceName {
+			return direction, nil
+		}
+
+	case ReferencedDirection:
+		if relation.toResource == resourceName {
+			return direction, nil
+		}
+
+	default:
+		return "", &UnknownRelationDirectionError{Direction: string(direction)}
+	}
+
+	return "", &RelationDirectionMismatchError{
+		Resource:  resourceName,
+		Relation:  relation.QualifiedName(),
+		Direction: direction,
+	}
+}
+
+func relationVia(relationships Relationships, resourceName string, relatedResourceName string, via string) (Relation, error) {
+	var relations []Relation
+	var relationNames []string
+	for _, relation := range relationships.Between(resourceName, relatedResourceName) {
+		if via == "" || relation.name == via {
+			relations = append(relations, relation)
+			relationNames = append(relationNames, relation.QualifiedName())
+		}
+	}
+
+	switch {
+	case len(relations) == 0:
+		return Relation{}, &NoRelationError{Resource: resourceName, RelatedResource: relatedResourceName, Via: via}
+	case len(relations) > 1:
+		return Relation{}, &AmbiguousRelationError{
+			Resource:        resourceName,
+			RelatedResource: relatedResourceName,
+			Relations:       relationNames,
+		}
+	}
+
+	return relations[0], nil
+}
@@ -0,0 +1,141 @@
+package configuration_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"entity-works/configuration"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sampleSelectionCriteria struct {
+	table    string
+	criteria string
+	related  []string
+	options  map[string]any
+}
+
+func init() {
+	configuration.RegisterSelectionCriteria(
+		"Sample",
+		func(definition configuration.SelectionCriteriaDefinition) (configuration.SelectionCriteria, error) {
+			if definition.Criteria == "" {
+				return nil, errors.New("sample criteria needs a percentage")
+			}
+
+			sampleSelectionCriteria := &sampleSelectionCriteria{
+				table:    definition.Element.Resource().TableName(),
+				criteria: definition.Criteria,
+				options:  definition.Options,
+			}
+			for _, element := range definition.Elements {
+				sampleSelectionCriteria.related = append(sampleSelectionCriteria.related, element.Name())
+			}
+
+			return sampleSelectionCriteria, nil
+		},
+	)
+}
+
+const sampleYmlConfiguration = `Name: Shop
+Resources:
+  Customers:
+    TableName: customers
+Entities:
+  Sales:
+    Components:
+      Customers:
+        Elements:
+          AllCustomers:
+            Resource: Customers
+            SelectionCriteria:
+              Type: Custom
+              Criteria: 1 = 1
+          SampledCustomers:
+            Resource: Customers
+            SelectionCriteria:
+              Type: Sample
+              Criteria: "10"
+              Elements:
+                - AllCustomers
+`
+
+func TestRegisteredSelectionCriteriaTypeIsBuilt(t *testing.T) {
+	loadedConfiguration, err := configuration.LoadReader(strings.NewReader(sampleYmlConfiguration))
+	assert.Nil(t, err)
+
+	sampledCustomers, _ := loadedConfiguration.Element(configuration.ElementPath{
+		Entity:    "Sales",
+		Component: "Customers",
+		Element:   "SampledCustomers",
+	})
+	assert.Equal(
+		t,
+		&sampleSelectionCriteria{table: "customers", criteria: "10", related: []string{"AllCustomers"}},
+		sampledCustomers.SelectionCriteria(),
+	)
+	assert.Contains(t, configuration.SelectionCriteriaTypes(), "Sample")
+}
+
+func TestRegisteredSelectionCriteriaTypeReceivesItsOwnOptions(t *testing.T) {
+	loadedConfiguration, err := configuration.LoadReader(strings.NewReader(strings.Replace(
+		sampleYmlConfiguration,
+		`Criteria: "10"`,
+		"Criteria: \"10\"\n              Seed: 42\n              Strata:\n                - country",
+		1,
+	)))
+	assert.Nil(t, err)
+
+	sampledCustomers, _ := loadedConfiguration.Element(configuration.ElementPath{
+		Entity:    "Sales",
+		Component: "Customers",
+		Element:   "SampledCustomers",
+	})
+	assert.Equal(
+		t,
+		map[string]any{"Seed": uint64(42), "Strata": []any{"country"}},
+		sampledCustomers.SelectionCriteria().(*sampleSelectionCriteria).options,
+	)
+}
+
+func TestBuiltInSelectionCriteriaTypesStillRejectUnknownFields(t *testing.T) {
+	_, err := configuration.LoadReader(strings.NewReader(strings.Replace(
+		sampleYmlConfiguration,
+		"Criteria: 1 = 1",
+		"Criteria: 1 = 1\n              Seed: 42",
+		1,
+	)))
+
+	var unknownFieldError *configuration.UnknownFieldError
+	assert.True(t, errors.As(err, &unknownFieldError))
+	assert.Equal(t, "Seed", unknownFieldError.Field)
+}
+
+func TestSelectionCriteriaFactoryErrorsFailTheBuild(t *testing.T) {
+	_, err := configuration.LoadReader(strings.NewReader(strings.Replace(sampleYmlConfiguration, `Criteria: "10"`, "", 1)))
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), `building element "SampledCustomers": building Sample selection criteria: sample criteria needs a percentage`)
+}
+
+func TestUnregisteredSelectionCriteriaTypeFailsTheBuild(t *testing.T) {
+	_, err := configuration.LoadReader(strings.NewReader(strings.Replace(sampleYmlConfiguration, "Type: Sample", "Type: Region", 1)))
+
+	var unknownSelectionCriteriaTypeError *configuration.UnknownSelectionCriteriaTypeError
+	assert.True(t, errors.As(err, &unknownSelectionCriteriaTypeError))
+	assert.Equal(t, "Region", unknownSelectionCriteriaTypeError.Type)
+}
+
+func TestRegisterSelectionCriteriaRejectsDuplicates(t *testing.T) {
+	assert.Subset(t, configuration.SelectionCriteriaTypes(), []string{"Custom", "Index", "Related", "Sample"})
+	assert.Panics(t, func() {
+		configuration.RegisterSelectionCriteria("Related", func(configuration.SelectionCriteriaDefinition) (configuration.SelectionCriteria, error) {
+			return nil, nil
+		})
+	})
+	assert.Panics(t, func() {
+		configuration.RegisterSelectionCriteria("Nil", nil)
+	})
+}
@@ -0,0 +1,78 @@
+package configuration
+
+import (
+	"fmt"
+	"strings"
+)
+
+const sharesSeparator = "::"
+
+type ElementPath struct {
+	Entity    string
+	Component string
+	Element   string
+}
+
+func NewElementPath(entity string, component string, element string) *ElementPath {
+	return &ElementPath{
+		Entity:    entity,
+		Component: component,
+		Element:   element,
+	}
+}
+
+func ParseElementPath(elementPath string) (ElementPath, error) {
+	segments := strings.Split(elementPath, sharesSeparator)
+	if len(segments) != 3 || segments[0] == "" || segments[1] == "" || segments[2] == "" {
+		return ElementPath{}, fmt.Errorf("element path %q must be of the form Entity::Component::Element", elementPath)
+	}
+
+	return *NewElementPath(segments[0], segments[1], segments[2]), nil
+}
+
+func (elementPath ElementPath) String() string {
+	return strings.Join([]string{elementPath.Entity, elementPath.Component, elementPath.Element}, sharesSeparator)
+}
+
+func (elementPath ElementPath) ymlPath() ymlPath {
+	return ymlPath{
+		"Entities", elementPath.Entity,
+		"Components", elementPath.Component,
+		"Elements", elementPath.Element,
+	}
+}
+
+func lookupYmlElement(ymlEntities map[string]YmlEntity, elementPath ElementPath) (YmlElement, bool) {
+	ymlElement, exists := ymlEntities[elementPath.Entity].Components[elementPath.Component].Elements[elementPath.Element]
+
+	return ymlElement, exists
+}
+
+func resolveShares(ymlEntities map[string]YmlEntity, elementPath ElementPath) (ElementPath, YmlElement, error) {
+	chain := []string{elementPath.String()}
+	for {
+		ymlElement, exists := lookupYmlElement(ymlEntities, elementPath)
+		if !exists {
+			return ElementPath{}, YmlElement{}, &UnknownElementError{Element: elementPath.String()}
+		}
+
+		if ymlElement.Shares == "" {
+			return elementPath, ymlElement, nil
+		}
+
+		sharedElementPath, err := ParseElementPath(ymlElement.Shares)
+		if err != nil {
+			return ElementPath{}, YmlElement{}, err
+		}
+
+		for index, chainedElementPath := range chain {
+			if chainedElementPath == sharedElementPath.String() {
+				cycle := append(append([]string{}, chain[index:]...), chainedElementPath)
+				return ElementPath{}, YmlElement{}, &CyclicElementDependencyError{Elements: cycle}
+			}
+		}
+
+		chain = append(chain, sharedElementPath.String())
+		elementPath = sharedElementPath
+	}
+}
@@ -0,0 +1,150 @@
+package configuration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sharesYmlConfiguration = `Name: Shop
+Resources:
+  Customers:
+    TableName: customers
+  Orders:
+    TableName: orders
+    ForeignKeys:
+      - Type: NORMAL
+        Key: orders.customer_id
+        ResourceName: Customers
+        ForeignKey: customers.id
+Entities:
+  CoreProduct:
+    Components:
+      Customers:
+        Elements:
+          Customers:
+            Resource: Customers
+            SelectionCriteria:
+              Type: Custom
+              Criteria: region = 'EU'
+      Orders:
+        Elements:
+          Customers:
+            Shares: CoreProduct::Customers::Customers
+          Orders:
+            Resource: Orders
+            SelectionCriteria:
+              Type: Related
+              Elements:
+                - Customers
+  Reporting:
+    Components:
+      Customers:
+        Elements:
+          Customers:
+            Resource: Customers
+            Shares: CoreProduct::Orders::Customers
+`
+
+func TestParseElementPath(t *testing.T) {
+	elementPath, err := ParseElementPath("CoreProduct::Customers::Customers")
+
+	assert.Nil(t, err)
+	assert.Equal(t, ElementPath{Entity: "CoreProduct", Component: "Customers", Element: "Customers"}, elementPath)
+	assert.Equal(t, "CoreProduct::Customers::Customers", elementPath.String())
+
+	_, err = ParseElementPath("CoreProduct::Customers")
+	assert.NotNil(t, err)
+	_, err = ParseElementPath("CoreProduct::::Customers")
+	assert.NotNil(t, err)
+}
+
+func TestConfigurationBuilderResolvesSharedElements(t *testing.T) {
+	configuration, err := NewConfigurationBuilderYml().Build(parseYmlSchema(t, sharesYmlConfiguration))
+	assert.Nil(t, err)
+
+	sharingElement := configuration.entities["CoreProduct"].components["Orders"].elements["Customers"]
+	assert.Equal(t, "customers", sharingElement.resource.tableName)
+	assert.Equal(t, &ElementPath{Entity: "CoreProduct", Component: "Customers", Element: "Customers"}, sharingElement.shares)
+	assert.Nil(t, sharingElement.selectionCriteria)
+
+	chainedElement := configuration.entities["Reporting"].components["Customers"].elements["Customers"]
+	assert.Equal(t, "customers", chainedElement.resource.tableName)
+	assert.Equal(t, "CoreProduct::Customers::Customers", chainedElement.shares.String())
+
+	sharedElement := configuration.entities["CoreProduct"].components["Customers"].elements["Customers"]
+	assert.Nil(t, sharedElement.shares)
+}
+
+func TestValidateReportsSharedResourceMismatch(t *testing.T) {
+	ymlSchema := parseYmlSchema(t, sharesYmlConfiguration+`          Orders:
+            Resource: Orders
+            Shares: CoreProduct::Customers::Customers
+`)
+
+	validationErrors := Validate(ymlSchema)
+
+	assert.Len(t, validationErrors, 1)
+	assert.Equal(t, "$.Entities.Reporting.Components.Customers.Elements.Orders.Resource", validationErrors[0].Path)
+	var sharedResourceMismatchError *SharedResourceMismatchError
+	assert.True(t, errors.As(validationErrors[0], &sharedResourceMismatchError))
+	assert.Equal(t, "Orders", sharedResourceMismatchError.Resource)
+	assert.Equal(t, "Customers", sharedResourceMismatchError.SharedResource)
+}
+
+func TestValidateReportsSharesCycles(t *testing.T) {
+	ymlSchema := parseYmlSchema(t, `Name: Shop
+Resources:
+  Customers:
+    TableName: customers
+Entities:
+  CoreProduct:
+    Components:
+      Customers:
+        Elements:
+          First:
+            Shares: CoreProduct::Customers::Second
+          Second:
+            Shares: CoreProduct::Customers::First
+`)
+
+	validationErrors := Validate(ymlSchema)
+
+	assert.Len(t, validationErrors, 2)
+	var cyclicElementDependencyError *CyclicElementDependencyError
+	assert.True(t, errors.As(validationErrors[0], &cyclicElementDependencyError))
+	assert.Equal(
+		t,
+		[]string{"CoreProduct::Customers::First", "CoreProduct::Customers::Second", "CoreProduct::Customers::First"},
+		cyclicElementDependencyError.Elements,
+	)
+}
+
+func TestValidateRejectsSelectionCriteriaOnSharingElement(t *testing.T) {
+	ymlSchema := parseYmlSchema(t, sharesYmlConfiguration+`          Orders:
+            Shares: CoreProduct::Customers::Customers
+            SelectionCriteria:
+              Type: Custom
+              Criteria: 1 = 1
+`)
+
+	validationErrors := Validate(ymlSchema)
+
+	assert.Len(t, validationErrors, 1)
+	assert.Equal(t, "$.Entities.Reporting.Components.Customers.Elements.Orders.SelectionCriteria", validationErrors[0].Path)
+}
+
+func TestValidateRejectsRecursionOnSharingElement(t *testing.T) {
+	ymlSchema := parseYmlSchema(t, sharesYmlConfiguration+`          Orders:
+            Shares: CoreProduct::Customers::Customers
+            SelectionCriteria:
+              Recursive:
+                Direction: Descendants
+`)
+
+	validationErrors := Validate(ymlSchema)
+
+	assert.Len(t, validationErrors, 1)
+	assert.Equal(t, "$.Entities.Reporting.Components.Customers.Elements.Orders.SelectionCriteria", validationErrors[0].Path)
+}
@@ -0,0 +1,542 @@
+package configuration
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type ValidationError struct {
+	File    string
+	Path    string
+	Line    int
+	Column  int
+	Message string
+	Err     error
+}
+
+func (validationError ValidationError) Error() string {
+	location := validationError.File
+	if validationError.Line > 0 {
+		location = fmt.Sprintf("%s:%d:%d", location, validationError.Line, validationError.Column)
+	}
+	location = strings.TrimPrefix(location, ":")
+
+	if location == "" {
+		return fmt.Sprintf("%s: %s", validationError.Path, validationError.Message)
+	}
+
+	return fmt.Sprintf("%s: %s: %s", location, validationError.Path, validationError.Message)
+}
+
+func (validationError ValidationError) Unwrap() error {
+	return validationError.Err
+}
+
+type ValidationErrors []ValidationError
+
+func (validationErrors ValidationErrors) Error() string {
+	messages := make([]string, 0, len(validationErrors))
+	for _, validationError := range validationErrors {
+		messages = append(messages, validationError.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+func (validationErrors ValidationErrors) Unwrap() []error {
+	errs := make([]error, 0, len(validationErrors))
+	for _, validationError := range validationErrors {
+		errs = append(errs, validationError)
+	}
+
+	return errs
+}
+
+type validator struct {
+	ymlSchema YmlSchema
+	errors    []ValidationError
+}
+
+func Validate(ymlSchema YmlSchema) []ValidationError {
+	validator := &validator{ymlSchema: ymlSchema}
+	validator.validateResources()
+	validator.validateEntities()
+
+	return validator.errors
+}
+
+func (validator *validator) report(path ymlPath, err error, format string, args ...any) {
+	file, line, column := validator.ymlSchema.position(path)
+	validator.errors = append(validator.errors, ValidationError{
+		File:    file,
+		Path:    path.String(),
+		Line:    line,
+		Column:  column,
+		Message: fmt.Sprintf(format, args...),
+		Err:     err,
+	})
+}
+
+func (validator *validator) validateResources() {
+	for _, resourceName := range sortedKeys(validator.ymlSchema.Resources) {
+		ymlResource := validator.ymlSchema.Resources[resourceName]
+		resourcePath := ymlPath{"Resources", resourceName}
+		table, tableErr := ParseTableRef(ymlResource.TableName)
+		if tableErr != nil {
+			validator.report(
+				resourcePath.child("TableName"),
+				&InvalidReferenceError{Reference: ymlResource.TableName, Err: tableErr},
+				"invalid table name: %s",
+				tableErr,
+			)
+		}
+
+		for index, column := range ymlResource.PrimaryKey {
+			validator.validateColumnRef(resourcePath.child("PrimaryKey", index), column, resourceName, table)
+		}
+
+		for _, indexName := range sortedKeys(ymlResource.Index) {
+			for index, column := range ymlResource.Index[indexName] {
+				validator.validateColumnRef(resourcePath.child("Index", indexName, index), column, resourceName, table)
+			}
+		}
+
+		foreignKeyNames := make(map[string]bool)
+		for index, ymlForeignKey := range ymlResource.ForeignKeys {
+			foreignKeyPath := resourcePath.child("ForeignKeys", index)
+			if name := foreignKeyName(ymlForeignKey); foreignKeyNames[name] {
+				validator.report(
+					foreignKeyPath,
+					&DuplicateForeignKeyError{Resource: resourceName, ForeignKey: name},
+					"resource %q has more than one foreign key named %q, give each one a unique Name",
+					resourceName,
+					name,
+				)
+			} else {
+				foreignKeyNames[name] = true
+			}
+
+			for keyIndex, column := range ymlForeignKey.Key {
+				validator.validateColumnRef(
+					columnsPath(foreignKeyPath.child("Key"), keyIndex, ymlForeignKey.Key),
+					column,
+					resourceName,
+					table,
+				)
+			}
+
+			if len(ymlForeignKey.Key) == 0 || len(ymlForeignKey.Key) != len(ymlForeignKey.ForeignKey) {
+				foreignKeyArityError := &ForeignKeyArityError{
+					Keys:        len(ymlForeignKey.Key),
+					ForeignKeys: len(ymlForeignKey.ForeignKey),
+				}
+				validator.report(foreignKeyPath, foreignKeyArityError, "%s", foreignKeyArityError)
+			}
+
+			validator.validateKeyType(foreignKeyPath, ymlForeignKey)
+
+			foreignYmlResource, exists := validator.ymlSchema.Resources[ymlForeignKey.ResourceName]
+			if !exists {
+				validator.report(
+					foreignKeyPath.child("ResourceName"),
+					&UnknownResourceError{Resource: ymlForeignKey.ResourceName},
+					"foreign key references unknown resource %q",
+					ymlForeignKey.ResourceName,
+				)
+				continue
+			}
+
+			foreignTable, err := ParseTableRef(foreignYmlResource.TableName)
+			if err != nil {
+				continue
+			}
+			for foreignKeyIndex, column := range ymlForeignKey.ForeignKey {
+				validator.validateColumnRef(
+					columnsPath(foreignKeyPath.child("ForeignKey"), foreignKeyIndex, ymlForeignKey.ForeignKey),
+					column,
+					ymlForeignKey.ResourceName,
+					foreignTable,
+				)
+			}
+		}
+	}
+}
+
+func (validator *validator) validateKeyType(foreignKeyPath ymlPath, ymlForeignKey YmlForeignKey) {
+	_, _, err := parseKeyType(ymlForeignKey)
+	if err == nil {
+		return
+	}
+
+	path := foreignKeyPath
+	switch err := err.(type) {
+	case *UnknownKeyTypeError:
+		path = foreignKeyPath.child("Type")
+	case *UnknownDelimitedFormatError:
+		path = foreignKeyPath.child("Format")
+	case *InvalidDelimiterError:
+		path = foreignKeyPath.child("Delimiter")
+	case *DelimitedKeyArityError:
+		path = foreignKeyPath.child("Key")
+	case *DelimitedOptionError:
+		path = foreignKeyPath.child(err.Option)
+	}
+
+	validator.report(path, err, "%s", err)
+}
+
+func (validator *validator) validateColumnRef(path ymlPath, reference string, resourceName string, table TableRef) {
+	columnRef, err := ParseColumnRef(reference)
+	if err != nil {
+		validator.report(path, &InvalidReferenceError{Reference: reference, Err: err}, "invalid column reference: %s", err)
+		return
+	}
+
+	if table.Table != "" && !columnRef.TableRef().Matches(table) {
+		validator.report(
+			path,
+			&ColumnTableMismatchError{Column: columnRef, Resource: resourceName, Table: table},
+			"column %q does not belong to table %q of resource %q",
+			columnRef.String(),
+			table.String(),
+			resourceName,
+		)
+	}
+}
+
+func (validator *validator) validateEntities() {
+	for _, entityName := range sortedKeys(validator.ymlSchema.Entities) {
+		ymlEntity := validator.ymlSchema.Entities[entityName]
+		for _, parameterName := range sortedKeys(ymlEntity.Parameters) {
+			validator.validateParameter(
+				ymlPath{"Entities", entityName, "Parameters", parameterName},
+				parameterName,
+				ymlEntity.Parameters[parameterName],
+			)
+		}
+
+		for _, componentName := range sortedKeys(ymlEntity.Components) {
+			ymlComponent := ymlEntity.Components[componentName]
+			for _, elementName := range sortedKeys(ymlComponent.Elements) {
+				validator.validateElement(
+					*NewElementPath(entityName, componentName, elementName),
+					ymlComponent,
+					ymlComponent.Elements[elementName],
+				)
+			}
+		}
+	}
+}
+
+func (validator *validator) validateElement(path ElementPath, ymlComponent YmlComponent, ymlElement YmlElement) {
+	elementPath := path.ymlPath()
+	if ymlElement.Resource == "" && ymlElement.Shares == "" {
+		validator.report(elementPath, nil, "element must declare a Resource or Shares")
+	}
+
+	ymlResource, resourceExists := validator.ymlSchema.Resources[ymlElement.Resource]
+	if ymlElement.Resource != "" && !resourceExists {
+		validator.report(
+			elementPath.child("Resource"),
+			&UnknownResourceError{Resource: ymlElement.Resource},
+			"element references unknown resource %q",
+			ymlElement.Resource,
+		)
+	}
+
+	if ymlElement.Shares != "" {
+		validator.validateShares(path, ymlElement)
+		return
+	}
+
+	ymlSelectionCriteria := ymlElement.SelectionCriteria
+	selectionCriteriaPath := elementPath.child("SelectionCriteria")
+	if !isKnownSelectionCriteriaType(ymlSelectionCriteria.Type) {
+		validator.report(
+			selectionCriteriaPath.child("Type"),
+			&UnknownSelectionCriteriaTypeError{Type: ymlSelectionCriteria.Type},
+			"unknown selection criteria type %q",
+			ymlSelectionCriteria.Type,
+		)
+	}
+
+	for index, relatedElementName := range ymlSelectionCriteria.Elements {
+		if _, exists := ymlComponent.Elements[relatedElementName]; !exists {
+			validator.report(
+				selectionCriteriaPath.child("Elements", index),
+				&UnknownElementError{Element: relatedElementName},
+				"selection criteria references unknown element %q",
+				relatedElementName,
+			)
+			continue
+		}
+
+		if ymlSelectionCriteria.Type == "Related" {
+			validator.validateRelation(
+				selectionCriteriaPath.child("Elements", index),
+				path,
+				*NewElementPath(path.Entity, path.Component, relatedElementName),
+				ymlSelectionCriteria.Via,
+			)
+		}
+	}
+
+	if ymlSelectionCriteria.Type == "Related" && ymlSelectionCriteria.Direction != "" &&
+		!isKnownRelationDirection(RelationDirection(ymlSelectionCriteria.Direction)) {
+		unknownRelationDirectionError := &UnknownRelationDirectionError{Direction: ymlSelectionCriteria.Direction}
+		validator.report(selectionCriteriaPath.child("Direction"), unknownRelationDirectionError, "%s", unknownRelationDirectionError)
+	}
+
+	if ymlSelectionCriteria.Type == "Custom" {
+		validator.validateCriteriaTemplate(selectionCriteriaPath.child("Criteria"), path, ymlSelectionCriteria.Criteria)
+	}
+
+	if ymlSelectionCriteria.Recursive != nil {
+		validator.validateRecursion(selectionCriteriaPath.child("Recursive"), path, *ymlSelectionCriteria.Recursive)
+	}
+
+	if ymlSelectionCriteria.Type == "Index" && resourceExists {
+		validator.validateIndex(selectionCriteriaPath, path, ymlElement.Resource, ymlResource, ymlSelectionCriteria)
+	}
+}
+
+func (validator *validator) validateIndex(
+	path ymlPath,
+	elementPath ElementPath,
+	resourceName string,
+	ymlResource YmlResource,
+	ymlSelectionCriteria YmlSelectionCriteria,
+) {
+	columns, exists := ymlResource.Index[ymlSelectionCriteria.Index]
+	if !exists {
+		unknownIndexError := &UnknownIndexError{Resource: resourceName, Index: ymlSelectionCriteria.Index}
+		validator.report(path.child("Index"), unknownIndexError, "%s", unknownIndexError)
+		return
+	}
+
+	for index, upstreamElementName := range ymlSelectionCriteria.Elements {
+		upstreamResourceName, resolved := validator.elementResource(
+			*NewElementPath(elementPath.Entity, elementPath.Component, upstreamElementName),
+		)
+		if !resolved {
+			continue
+		}
+
+		upstreamColumns := validator.ymlSchema.Resources[upstreamResourceName].PrimaryKey
+		if len(upstreamColumns) != len(columns) {
+			indexArityError := &IndexArityError{
+				Resource:         resourceName,
+				Index:            ymlSelectionCriteria.Index,
+				Columns:          len(columns),
+				Upstream:         upstreamElementName,
+				UpstreamResource: upstreamResourceName,
+				UpstreamColumns:  len(upstreamColumns),
+			}
+			validator.report(path.child("Elements", index), indexArityError, "%s", indexArityError)
+		}
+	}
+}
+
+func (validator *validator) validateRelation(
+	path ymlPath,
+	elementPath ElementPath,
+	relatedElementPath ElementPath,
+	via string,
+) {
+	resourceName, resolved := validator.elementResource(elementPath)
+	relatedResourceName, relatedResolved := validator.elementResource(relatedElementPath)
+	if !resolved || !relatedResolved {
+		return
+	}
+
+	validator.validateRelationBetween(path, resourceName, relatedResourceName, via)
+}
+
+func (validator *validator) validateRelationBetween(path ymlPath, resourceName string, relatedResourceName string, via string) {
+	var relationNames []string
+	for _, relationName := range validator.relationsBetween(resourceName, relatedResourceName) {
+		if via == "" || strings.HasSuffix(relationName, sharesSeparator+via) {
+			relationNames = append(relationNames, relationName)
+		}
+	}
+
+	switch {
+	case len(relationNames) == 0:
+		noRelationError := &NoRelationError{Resource: resourceName, RelatedResource: relatedResourceName, Via: via}
+		validator.report(path, noRelationError, "%s", noRelationError)
+
+	case len(relationNames) > 1:
+		ambiguousRelationError := &AmbiguousRelationError{
+			Resource:        resourceName,
+			RelatedResource: relatedResourceName,
+			Relations:       relationNames,
+		}
+		validator.report(path, ambiguousRelationError, "%s", ambiguousRelationError)
+	}
+}
+
+func (validator *validator) validateParameter(path ymlPath, parameterName string, ymlParameter YmlParameter) {
+	_, err := NewParameter(parameterName, ymlParameter)
+	switch err.(type) {
+	case nil:
+	case *UnknownParameterTypeError:
+		validator.report(path.child("Type"), err, "%s", err)
+	default:
+		validator.report(path.child("Default"), err, "%s", err)
+	}
+}
+
+func (validator *validator) validateCriteriaTemplate(path ymlPath, elementPath ElementPath, criteria string) {
+	template, err := ParseCriteriaTemplate(criteria)
+	if err != nil {
+		validator.report(path, err, "invalid criteria: %s", err)
+		return
+	}
+
+	ymlParameters := validator.ymlSchema.Entities[elementPath.Entity].Parameters
+	for _, parameterName := range template.Parameters() {
+		if _, exists := ymlParameters[parameterName]; !exists {
+			validator.report(
+				path,
+				&UnknownParameterError{Parameter: parameterName},
+				"criteria uses parameter %q which entity %q does not declare",
+				parameterName,
+				elementPath.Entity,
+			)
+		}
+	}
+}
+
+func (validator *validator) validateRecursion(path ymlPath, elementPath ElementPath, ymlRecursion YmlRecursion) {
+	if !isKnownRecursionDirection(RecursionDirection(ymlRecursion.Direction)) {
+		unknownRecursionDirectionError := &UnknownRecursionDirectionError{Direction: ymlRecursion.Direction}
+		validator.report(path.child("Direction"), unknownRecursionDirectionError, "%s", unknownRecursionDirectionError)
+	}
+
+	if ymlRecursion.MaxDepth < 0 {
+		validator.report(path.child("MaxDepth"), nil, "recursion MaxDepth must not be negative, got %d", ymlRecursion.MaxDepth)
+	}
+
+	resourceName, resolved := validator.elementResource(elementPath)
+	if !resolved {
+		return
+	}
+
+	validator.validateRelationBetween(path, resourceName, resourceName, ymlRecursion.Via)
+}
+
+func (validator *validator) elementResource(elementPath ElementPath) (string, bool) {
+	_, ymlElement, err := resolveShares(validator.ymlSchema.Entities, elementPath)
+	if err != nil {
+		return "", false
+	}
+
+	_, exists := validator.ymlSchema.Resources[ymlElement.Resource]
+	return ymlElement.Resource, exists
+}
+
+func (validator *validator) relationsBetween(resourceName string, otherResourceName string) []string {
+	var relationNames []string
+	for _, ymlForeignKey := range validator.ymlSchema.Resources[resourceName].ForeignKeys {
+		if ymlForeignKey.ResourceName == otherResourceName {
+			relationNames = append(relationNames, relationKey(resourceName, foreignKeyName(ymlForeignKey)))
+		}
+	}
+
+	if resourceName != otherResourceName {
+		for _, ymlForeignKey := range validator.ymlSchema.Resources[otherResourceName].ForeignKeys {
+			if ymlForeignKey.ResourceName == resourceName {
+				relationNames = append(relationNames, relationKey(otherResourceName, foreignKeyName(ymlForeignKey)))
+			}
+		}
+	}
+	sort.Strings(relationNames)
+
+	return relationNames
+}
+
+func (validator *validator) validateShares(path ElementPath, ymlElement YmlElement) {
+	shares := ymlElement.Shares
+	sharesPath := path.ymlPath().child("Shares")
+	sharedElementPath, err := ParseElementPath(shares)
+	if err != nil {
+		validator.report(sharesPath, nil, "shares %q must be of the form Entity::Component::Element", shares)
+		return
+	}
+
+	ymlEntity, exists := validator.ymlSchema.Entities[sharedElementPath.Entity]
+	if !exists {
+		validator.report(sharesPath, &UnknownElementError{Element: shares}, "shares references unknown entity %q", sharedElementPath.Entity)
+		return
+	}
+
+	ymlComponent, exists := ymlEntity.Components[sharedElementPath.Component]
+	if !exists {
+		validator.report(sharesPath, &UnknownElementError{Element: shares}, "shares references unknown component %q", sharedElementPath.Entity+sharesSeparator+sharedElementPath.Component)
+		return
+	}
+
+	if _, exists := ymlComponent.Elements[sharedElementPath.Element]; !exists {
+		validator.report(sharesPath, &UnknownElementError{Element: shares}, "shares references unknown element %q", shares)
+		return
+	}
+
+	if !ymlElement.SelectionCriteria.isEmpty() {
+		validator.report(
+			path.ymlPath().child("SelectionCriteria"),
+			nil,
+			"element shares %q and cannot declare its own SelectionCriteria",
+			shares,
+		)
+	}
+
+	resolvedElementPath, resolvedYmlElement, err := resolveShares(validator.ymlSchema.Entities, path)
+	if err != nil {
+		validator.report(sharesPath, err, "shares %q cannot be resolved: %s", shares, err)
+		return
+	}
+
+	if ymlElement.Resource != "" && ymlElement.Resource != resolvedYmlElement.Resource {
+		validator.report(
+			path.ymlPath().child("Resource"),
+			&SharedResourceMismatchError{
+				Element:        path.String(),
+				Resource:       ymlElement.Resource,
+				SharedElement:  resolvedElementPath.String(),
+				SharedResource: resolvedYmlElement.Resource,
+			},
+			"element resource %q does not match resource %q of shared element %q",
+			ymlElement.Resource,
+			resolvedYmlElement.Resource,
+			resolvedElementPath.String(),
+		)
+	}
+}
+
+func columnsPath(path ymlPath, index int, ymlColumns YmlColumns) ymlPath {
+	if len(ymlColumns) == 1 {
+		return path
+	}
+
+	return path.child(index)
+}
+
+func isKnownSelectionCriteriaType(selectionCriteriaType string) bool {
+	if selectionCriteriaType == "" {
+		return true
+	}
+
+	_, exists := lookupSelectionCriteriaFactory(selectionCriteriaType)
+	return exists
+}
+
+func sortedKeys[V any](items map[string]V) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,193 @@
+package configuration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func parseYmlSchema(t *testing.T, ymlConfiguration string) YmlSchema {
+	ymlSchema, err := NewYmlParser().Parse(ymlConfiguration)
+	assert.Nil(t, err)
+
+	return ymlSchema
+}
+
+func TestValidateAcceptsConsistentSchema(t *testing.T) {
+	validationErrors := Validate(getYmlSchema())
+
+	assert.Empty(t, validationErrors)
+}
+
+func TestValidateReportsForeignKeyToUnknownResource(t *testing.T) {
+	ymlSchema := parseYmlSchema(t, `Name: Example
+Resources:
+  MyTestResource:
+    TableName: my_test_table
+    ForeignKeys:
+      - Type: NORMAL
+        Key: my_test_table.fk1
+        ResourceName: MissingResource
+        ForeignKey: missing_table.id
+`)
+
+	validationErrors := Validate(ymlSchema)
+
+	assert.Len(t, validationErrors, 1)
+	assert.Equal(t, "$.Resources.MyTestResource.ForeignKeys[0].ResourceName", validationErrors[0].Path)
+	assert.Equal(t, 8, validationErrors[0].Line)
+	assert.Equal(t, 23, validationErrors[0].Column)
+	assert.Contains(t, validationErrors[0].Message, `"MissingResource"`)
+}
+
+func TestValidateReportsEveryDanglingElementReference(t *testing.T) {
+	ymlSchema := parseYmlSchema(t, `Name: Example
+Resources:
+  MyTestResource:
+    TableName: my_test_table
+    Index:
+      IDX1:
+        - my_test_table.idx1
+Entities:
+  MyTestEntity:
+    Components:
+      MyTestComponent1:
+        Elements:
+          ElementA:
+            Resource: MyTestResource
+          ElementB:
+            Resource: MyTestResource3
+          ElementC:
+            Resource: MyTestResource
+            SelectionCriteria:
+              Type: Index
+              Elements:
+                - ElementA
+                - ElementX
+              Index: IDX9
+          ElementD:
+            SelectionCriteria:
+              Type: Custom
+`)
+
+	validationErrors := Validate(ymlSchema)
+
+	assert.Len(t, validationErrors, 4)
+	assert.Equal(t, "$.Entities.MyTestEntity.Components.MyTestComponent1.Elements.ElementB.Resource", validationErrors[0].Path)
+	assert.Equal(t, 16, validationErrors[0].Line)
+	assert.Equal(t, "$.Entities.MyTestEntity.Components.MyTestComponent1.Elements.ElementC.SelectionCriteria.Elements[1]", validationErrors[1].Path)
+	assert.Equal(t, 23, validationErrors[1].Line)
+	assert.Equal(t, "$.Entities.MyTestEntity.Components.MyTestComponent1.Elements.ElementC.SelectionCriteria.Index", validationErrors[2].Path)
+	assert.Equal(t, 24, validationErrors[2].Line)
+	assert.Equal(t, "$.Entities.MyTestEntity.Components.MyTestComponent1.Elements.ElementD", validationErrors[3].Path)
+	assert.Equal(t, 26, validationErrors[3].Line)
+}
+
+func TestValidateReportsInvalidSharesPaths(t *testing.T) {
+	ymlSchema := parseYmlSchema(t, `Name: Example
+Resources:
+  MyTestResource:
+    TableName: my_test_table
+Entities:
+  MyTestEntity:
+    Components:
+      MyTestComponent1:
+        Elements:
+          ElementA:
+            Resource: MyTestResource
+          ElementB:
+            Shares: MyTestEntity::MyTestComponent1::ElementA
+          ElementC:
+            Shares: MyTestEntity::MyTestComponent1::ElementX
+          ElementD:
+            Shares: MyTestEntity::MyTestComponent2::ElementA
+          ElementE:
+            Shares: true
+`)
+
+	validationErrors := Validate(ymlSchema)
+
+	assert.Len(t, validationErrors, 3)
+	assert.Contains(t, validationErrors[0].Message, "unknown element")
+	assert.Equal(t, 15, validationErrors[0].Line)
+	assert.Contains(t, validationErrors[1].Message, "unknown component")
+	assert.Equal(t, 17, validationErrors[1].Line)
+	assert.Contains(t, validationErrors[2].Message, "Entity::Component::Element")
+	assert.Equal(t, 19, validationErrors[2].Line)
+}
+
+func TestValidationErrorWithoutSourceHasNoPosition(t *testing.T) {
+	ymlSchema := YmlSchema{
+		Resources: map[string]YmlResource{
+			"MyTestResource": {
+				TableName: "my_test_table",
+				ForeignKeys: []YmlForeignKey{
+					{
+						Type:         "NORMAL",
+						Key:          YmlColumns{"my_test_table.fk1"},
+						ResourceName: "MissingResource",
+						ForeignKey:   YmlColumns{"missing_table.id"},
+					},
+				},
+			},
+		},
+	}
+
+	validationErrors := Validate(ymlSchema)
+
+	assert.Len(t, validationErrors, 1)
+	assert.Equal(t, 0, validationErrors[0].Line)
+	assert.Equal(
+		t,
+		`$.Resources.MyTestResource.ForeignKeys[0].ResourceName: foreign key references unknown resource "MissingResource"`,
+		validationErrors[0].Error(),
+	)
+}
+
+func TestConfigurationBuilderRefusesToBuildInvalidSchema(t *testing.T) {
+	ymlSchema := parseYmlSchema(t, `Name: Example
+Entities:
+  MyTestEntity:
+    Components:
+      MyTestComponent1:
+        Elements:
+          ElementA:
+            Resource: MissingResource
+`)
+
+	configuration, err := NewConfigurationBuilderYml().Build(ymlSchema)
+
+	assert.Nil(t, configuration)
+	var validationErrors ValidationErrors
+	assert.True(t, errors.As(err, &validationErrors))
+	assert.Len(t, validationErrors, 1)
+	var unknownResourceError *UnknownResourceError
+	assert.True(t, errors.As(err, &unknownResourceError))
+	assert.Equal(t, "MissingResource", unknownResourceError.Resource)
+}
+
+func TestValidateReportsUnknownSelectionCriteriaType(t *testing.T) {
+	ymlSchema := parseYmlSchema(t, `Name: Example
+Resources:
+  Regions:
+    TableName: regions
+Entities:
+  MyTestEntity:
+    Components:
+      MyTestComponent1:
+        Elements:
+          Regions:
+            Resource: Regions
+            SelectionCriteria:
+              Type: Region
+`)
+
+	validationErrors := Validate(ymlSchema)
+
+	assert.Len(t, validationErrors, 1)
+	assert.Equal(t, 13, validationErrors[0].Line)
+	var unknownSelectionCriteriaTypeError *UnknownSelectionCriteriaTypeError
+	assert.True(t, errors.As(validationErrors[0], &unknownSelectionCriteriaTypeError))
+	assert.Equal(t, "Region", unknownSelectionCriteriaTypeError.Type)
+}
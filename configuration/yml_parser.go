@@ -1,14 +1,43 @@
 package configuration
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
 	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
 )
 
+type YmlColumns []string
+
+func (ymlColumns *YmlColumns) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var column string
+	if err := unmarshal(&column); err == nil {
+		*ymlColumns = YmlColumns{column}
+		return nil
+	}
+
+	var columns []string
+	if err := unmarshal(&columns); err != nil {
+		return err
+	}
+
+	*ymlColumns = columns
+	return nil
+}
+
 type YmlForeignKey struct {
-	Type         string `yaml:"Type"`
-	Key          string `yaml:"Key"`
-	ResourceName string `yaml:"ResourceName"`
-	ForeignKey   string `yaml:"ForeignKey"`
+	Name         string     `yaml:"Name,omitempty"`
+	Type         string     `yaml:"Type"`
+	Key          YmlColumns `yaml:"Key"`
+	ResourceName string     `yaml:"ResourceName"`
+	ForeignKey   YmlColumns `yaml:"ForeignKey"`
+	Format       string     `yaml:"Format,omitempty"`
+	Delimiter    string     `yaml:"Delimiter,omitempty"`
+	Trim         bool       `yaml:"Trim,omitempty"`
 }
 
 type YmlResource struct {
@@ -19,11 +48,59 @@ type YmlResource struct {
 	ForeignKeys   []YmlForeignKey     `yaml:"ForeignKeys,omitempty"`
 }
 
+type YmlRecursion struct {
+	Direction string `yaml:"Direction"`
+	Via       string `yaml:"Via,omitempty"`
+	MaxDepth  int    `yaml:"MaxDepth,omitempty"`
+}
+
 type YmlSelectionCriteria struct {
-	Type     string   `yaml:"Type"`
-	Criteria string   `yaml:"Criteria,omitempty"`
-	Elements []string `yaml:"Elements,omitempty"`
-	Index    string   `yaml:"Index,omitempty"`
+	Type      string        `yaml:"Type"`
+	Criteria  string        `yaml:"Criteria,omitempty"`
+	Elements  []string      `yaml:"Elements,omitempty"`
+	Index     string        `yaml:"Index,omitempty"`
+	Via       string        `yaml:"Via,omitempty"`
+	Direction string        `yaml:"Direction,omitempty"`
+	Recursive *YmlRecursion `yaml:"Recursive,omitempty"`
+
+	Options map[string]any `yaml:"-"`
+}
+
+func (ymlSelectionCriteria *YmlSelectionCriteria) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plainYmlSelectionCriteria YmlSelectionCriteria
+	if err := unmarshal((*plainYmlSelectionCriteria)(ymlSelectionCriteria)); err != nil {
+		return err
+	}
+
+	var fields map[string]any
+	if err := unmarshal(&fields); err != nil {
+		return err
+	}
+
+	knownFields := ymlFieldTypes(reflect.TypeOf(YmlSelectionCriteria{}))
+	for field, value := range fields {
+		if _, known := knownFields[field]; known {
+			continue
+		}
+
+		if ymlSelectionCriteria.Options == nil {
+			ymlSelectionCriteria.Options = make(map[string]any)
+		}
+		ymlSelectionCriteria.Options[field] = value
+	}
+
+	return nil
+}
+
+func (ymlSelectionCriteria YmlSelectionCriteria) isEmpty() bool {
+	return ymlSelectionCriteria.Type == "" &&
+		ymlSelectionCriteria.Criteria == "" &&
+		len(ymlSelectionCriteria.Elements) == 0 &&
+		ymlSelectionCriteria.Index == "" &&
+		ymlSelectionCriteria.Via == "" &&
+		ymlSelectionCriteria.Direction == "" &&
+		ymlSelectionCriteria.Recursive == nil &&
+		len(ymlSelectionCriteria.Options) == 0
 }
 
 type YmlElement struct {
@@ -37,16 +114,33 @@ type YmlComponent struct {
 	Elements    map[string]YmlElement `yaml:"Elements,omitempty"`
 }
 
+type YmlParameter struct {
+	Type     string `yaml:"Type,omitempty"`
+	Required bool   `yaml:"Required,omitempty"`
+	Default  any    `yaml:"Default,omitempty"`
+}
+
 type YmlEntity struct {
 	Description string                  `yaml:"Description"`
+	Extend      bool                    `yaml:"Extend,omitempty"`
+	Parameters  map[string]YmlParameter `yaml:"Parameters,omitempty"`
 	Components  map[string]YmlComponent `yaml:"Components,omitempty"`
 }
 
 type YmlSchema struct {
 	Name        string                 `yaml:"Name"`
 	Description string                 `yaml:"Description"`
+	Include     []string               `yaml:"Include,omitempty"`
 	Resources   map[string]YmlResource `yaml:"Resources"`
 	Entities    map[string]YmlEntity   `yaml:"Entities,omitempty"`
+
+	source  *ymlSource
+	origins map[string]*ymlSource
+}
+
+type ymlSource struct {
+	name string
+	file *ast.File
 }
 
 func NewYmlSchema() *YmlSchema {
@@ -54,18 +148,224 @@ func NewYmlSchema() *YmlSchema {
 }
 
 type Parser interface {
-	Parse(ymlConfiguration string) YmlSchema
+	Parse(ymlConfiguration string) (YmlSchema, error)
 }
 
 type YmlParser struct {
+	strict bool
 }
 
 func (ymlParser YmlParser) Parse(ymlConfiguration string) (YmlSchema, error) {
 	ymlDefinition := NewYmlSchema()
 	err := yaml.Unmarshal([]byte(ymlConfiguration), ymlDefinition)
-	return *ymlDefinition, err
+	if err != nil {
+		return *ymlDefinition, err
+	}
+
+	file, err := parser.ParseBytes([]byte(ymlConfiguration), 0)
+	if err != nil {
+		return *ymlDefinition, err
+	}
+	ymlDefinition.source = &ymlSource{file: file}
+
+	if ymlParser.strict {
+		if validationErrors := unknownFields(file); len(validationErrors) > 0 {
+			return *ymlDefinition, ValidationErrors(validationErrors)
+		}
+	}
+
+	return *ymlDefinition, nil
 }
 
 func NewYmlParser() *YmlParser {
 	return &YmlParser{}
 }
+
+func NewStrictYmlParser() *YmlParser {
+	return &YmlParser{strict: true}
+}
+
+type ymlPath []any
+
+func (path ymlPath) child(segments ...any) ymlPath {
+	childPath := make(ymlPath, 0, len(path)+len(segments))
+	childPath = append(childPath, path...)
+
+	return append(childPath, segments...)
+}
+
+func (path ymlPath) build() *yaml.Path {
+	pathBuilder := (&yaml.PathBuilder{}).Root()
+	for _, segment := range path {
+		switch segment := segment.(type) {
+		case string:
+			pathBuilder = pathBuilder.Child(segment)
+		case int:
+			pathBuilder = pathBuilder.Index(uint(segment))
+		}
+	}
+
+	return pathBuilder.Build()
+}
+
+func (path ymlPath) String() string {
+	return path.build().String()
+}
+
+func (ymlSchema YmlSchema) position(path ymlPath) (string, int, int) {
+	source := ymlSchema.sourceOf(path)
+	if source == nil {
+		return "", 0, 0
+	}
+
+	for length := len(path); length >= 0 && source.file != nil; length-- {
+		node, err := path[:length].build().FilterFile(source.file)
+		if err != nil || node == nil {
+			continue
+		}
+
+		position := node.GetToken().Position
+		return source.name, position.Line, position.Column
+	}
+
+	return source.name, 0, 0
+}
+
+func (ymlSchema YmlSchema) sourceOf(path ymlPath) *ymlSource {
+	if len(path) >= 4 {
+		if origin, exists := ymlSchema.origins[originKey(path[:4]...)]; exists {
+			return origin
+		}
+	}
+	if len(path) >= 2 {
+		if origin, exists := ymlSchema.origins[originKey(path[:2]...)]; exists {
+			return origin
+		}
+	}
+
+	return ymlSchema.source
+}
+
+func (ymlSchema *YmlSchema) setSourceName(name string) {
+	if ymlSchema.source == nil {
+		ymlSchema.source = &ymlSource{}
+	}
+
+	ymlSchema.source.name = name
+}
+
+func (ymlSchema *YmlSchema) merge(included YmlSchema) error {
+	if ymlSchema.Resources == nil {
+		ymlSchema.Resources = make(map[string]YmlResource)
+	}
+	if ymlSchema.Entities == nil {
+		ymlSchema.Entities = make(map[string]YmlEntity)
+	}
+	if ymlSchema.origins == nil {
+		ymlSchema.origins = make(map[string]*ymlSource)
+	}
+
+	var errs []error
+	for _, resourceName := range sortedKeys(included.Resources) {
+		if _, exists := ymlSchema.Resources[resourceName]; exists {
+			errs = append(errs, &DuplicateResourceError{
+				Resource: resourceName,
+				Files:    ymlSchema.sourceNames(ymlPath{"Resources", resourceName}, included),
+			})
+			continue
+		}
+
+		ymlSchema.Resources[resourceName] = included.Resources[resourceName]
+		ymlSchema.origins[originKey("Resources", resourceName)] = included.sourceOf(ymlPath{"Resources", resourceName})
+	}
+
+	for _, entityName := range sortedKeys(included.Entities) {
+		existing, exists := ymlSchema.Entities[entityName]
+		if !exists {
+			ymlSchema.Entities[entityName] = included.Entities[entityName]
+			ymlSchema.origins[originKey("Entities", entityName)] = included.sourceOf(ymlPath{"Entities", entityName})
+			for componentName := range included.Entities[entityName].Components {
+				componentPath := ymlPath{"Entities", entityName, "Components", componentName}
+				ymlSchema.origins[originKey(componentPath...)] = included.sourceOf(componentPath)
+			}
+			continue
+		}
+
+		merged, err := ymlSchema.mergeEntity(entityName, existing, included)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		ymlSchema.Entities[entityName] = merged
+	}
+
+	return errors.Join(errs...)
+}
+
+func (ymlSchema *YmlSchema) mergeEntity(entityName string, existing YmlEntity, included YmlSchema) (YmlEntity, error) {
+	includedEntity := included.Entities[entityName]
+	if (!existing.Extend && !includedEntity.Extend) ||
+		(existing.Description != "" && includedEntity.Description != "") ||
+		(len(existing.Parameters) > 0 && len(includedEntity.Parameters) > 0) {
+		return existing, &DuplicateEntityError{
+			Entity: entityName,
+			Files:  ymlSchema.sourceNames(ymlPath{"Entities", entityName}, included),
+		}
+	}
+
+	if existing.Description == "" {
+		existing.Description = includedEntity.Description
+	}
+	if len(existing.Parameters) == 0 {
+		existing.Parameters = includedEntity.Parameters
+	}
+	existing.Extend = existing.Extend && includedEntity.Extend
+
+	components := make(map[string]YmlComponent, len(existing.Components)+len(includedEntity.Components))
+	for componentName, component := range existing.Components {
+		components[componentName] = component
+	}
+
+	var errs []error
+	for _, componentName := range sortedKeys(includedEntity.Components) {
+		componentPath := ymlPath{"Entities", entityName, "Components", componentName}
+		if _, exists := components[componentName]; exists {
+			errs = append(errs, &DuplicateComponentError{
+				Entity:    entityName,
+				Component: componentName,
+				Files:     ymlSchema.sourceNames(componentPath, included),
+			})
+			continue
+		}
+
+		components[componentName] = includedEntity.Components[componentName]
+		ymlSchema.origins[originKey(componentPath...)] = included.sourceOf(componentPath)
+	}
+	existing.Components = components
+
+	return existing, errors.Join(errs...)
+}
+
+func (ymlSchema YmlSchema) sourceNames(path ymlPath, included YmlSchema) []string {
+	var names []string
+	for _, source := range []*ymlSource{
+		ymlSchema.sourceOf(path),
+		included.sourceOf(path),
+	} {
+		if source != nil {
+			names = append(names, source.name)
+		}
+	}
+
+	return names
+}
+
+func originKey(segments ...any) string {
+	parts := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		parts = append(parts, fmt.Sprint(segment))
+	}
+
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,165 @@
+package configuration
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+)
+
+func unknownFields(file *ast.File) []ValidationError {
+	var validationErrors []ValidationError
+	for _, document := range file.Docs {
+		if document.Body != nil {
+			checkFields(document.Body, reflect.TypeOf(YmlSchema{}), ymlPath{}, &validationErrors)
+		}
+	}
+
+	return validationErrors
+}
+
+func checkFields(node ast.Node, fieldType reflect.Type, path ymlPath, validationErrors *[]ValidationError) {
+	switch typedNode := node.(type) {
+	case *ast.AnchorNode:
+		checkFields(typedNode.Value, fieldType, path, validationErrors)
+		return
+	case *ast.TagNode:
+		checkFields(typedNode.Value, fieldType, path, validationErrors)
+		return
+	}
+
+	for fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Struct:
+		fieldTypes := ymlFieldTypes(fieldType)
+		acceptsOptions := fieldType == reflect.TypeOf(YmlSelectionCriteria{}) &&
+			selectionCriteriaAcceptsOptions(scalarField(node, "Type"))
+		for _, mappingValue := range mappingValues(node) {
+			key := mappingValue.Key.GetToken().Value
+			if mappingValue.Key.IsMergeKey() {
+				continue
+			}
+
+			keyType, exists := fieldTypes[key]
+			if !exists && acceptsOptions {
+				continue
+			}
+			if !exists {
+				unknownFieldError := &UnknownFieldError{Field: key, Suggestion: suggestField(key, sortedKeys(fieldTypes))}
+				position := mappingValue.Key.GetToken().Position
+				*validationErrors = append(*validationErrors, ValidationError{
+					Path:    path.child(key).String(),
+					Line:    position.Line,
+					Column:  position.Column,
+					Message: unknownFieldError.Error(),
+					Err:     unknownFieldError,
+				})
+				continue
+			}
+
+			checkFields(mappingValue.Value, keyType, path.child(key), validationErrors)
+		}
+
+	case reflect.Map:
+		for _, mappingValue := range mappingValues(node) {
+			key := mappingValue.Key.GetToken().Value
+			checkFields(mappingValue.Value, fieldType.Elem(), path.child(key), validationErrors)
+		}
+
+	case reflect.Slice:
+		sequence, isSequence := node.(*ast.SequenceNode)
+		if !isSequence {
+			return
+		}
+
+		for index, value := range sequence.Values {
+			checkFields(value, fieldType.Elem(), path.child(index), validationErrors)
+		}
+	}
+}
+
+func scalarField(node ast.Node, field string) string {
+	for _, mappingValue := range mappingValues(node) {
+		if mappingValue.Key.GetToken().Value == field && mappingValue.Value.GetToken() != nil {
+			return mappingValue.Value.GetToken().Value
+		}
+	}
+
+	return ""
+}
+
+func mappingValues(node ast.Node) []*ast.MappingValueNode {
+	switch node := node.(type) {
+	case *ast.MappingNode:
+		return node.Values
+	case *ast.MappingValueNode:
+		return []*ast.MappingValueNode{node}
+	}
+
+	return nil
+}
+
+func ymlFieldTypes(structType reflect.Type) map[string]reflect.Type {
+	fieldTypes := make(map[string]reflect.Type)
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldTypes[name] = field.Type
+	}
+
+	return fieldTypes
+}
+
+func suggestField(field string, knownFields []string) string {
+	suggestion := ""
+	bestDistance := max(1, len(field)/3) + 1
+	for _, knownField := range knownFields {
+		distance := editDistance(strings.ToLower(field), strings.ToLower(knownField))
+		if distance < bestDistance {
+			suggestion = knownField
+			bestDistance = distance
+		}
+	}
+
+	return suggestion
+}
+
+func editDistance(from string, to string) int {
+	fromRunes := []rune(from)
+	toRunes := []rune(to)
+
+	previous := make([]int, len(toRunes)+1)
+	current := make([]int, len(toRunes)+1)
+	for index := range previous {
+		previous[index] = index
+	}
+
+	for fromIndex := 1; fromIndex <= len(fromRunes); fromIndex++ {
+		current[0] = fromIndex
+		for toIndex := 1; toIndex <= len(toRunes); toIndex++ {
+			substitution := previous[toIndex-1]
+			if fromRunes[fromIndex-1] != toRunes[toIndex-1] {
+				substitution++
+			}
+
+			current[toIndex] = min(previous[toIndex]+1, current[toIndex-1]+1, substitution)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(toRunes)]
+}
@@ -0,0 +1,105 @@
+package configuration
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrictYmlParserReportsUnknownFieldsWithSuggestions(t *testing.T) {
+	ymlConfiguration, err := os.ReadFile("../resources/imperfect_online_shop.yml")
+	assert.Nil(t, err)
+
+	_, err = NewStrictYmlParser().Parse(string(ymlConfiguration))
+
+	var validationErrors ValidationErrors
+	assert.True(t, errors.As(err, &validationErrors))
+	assert.Equal(t, "$.Resources.Orders.PrimaryKeys", validationErrors[0].Path)
+	assert.Equal(t, 97, validationErrors[0].Line)
+	assert.Equal(t, 5, validationErrors[0].Column)
+	assert.Equal(t, `unknown field "PrimaryKeys", did you mean "PrimaryKey"?`, validationErrors[0].Message)
+
+	var primaryKeysPaths []string
+	for _, validationError := range validationErrors {
+		var unknownFieldError *UnknownFieldError
+		assert.True(t, errors.As(validationError, &unknownFieldError))
+		if unknownFieldError.Field == "PrimaryKeys" {
+			assert.Equal(t, "PrimaryKey", unknownFieldError.Suggestion)
+			primaryKeysPaths = append(primaryKeysPaths, validationError.Path)
+		}
+	}
+	assert.Equal(
+		t,
+		[]string{
+			"$.Resources.Orders.PrimaryKeys",
+			"$.Resources.OrderItems.PrimaryKeys",
+			"$.Resources.Payments.PrimaryKeys",
+			"$.Resources.Reviews.PrimaryKeys",
+		},
+		primaryKeysPaths,
+	)
+}
+
+func TestStrictYmlParserChecksNestedFields(t *testing.T) {
+	_, err := NewStrictYmlParser().Parse(`Name: Example
+Resources:
+  MyTestResource:
+    TableName: my_test_table
+    ForeignKeys:
+      - Type: NORMAL
+        Key: my_test_table.fk1
+        ResourceNmae: MyTestResource2
+        ForeignKey: my_test_table2.id
+Entities:
+  MyTestEntity:
+    Components:
+      MyTestComponent1:
+        Elements:
+          ElementA:
+            Resource: MyTestResource
+            selectionCriteria:
+              Type: Custom
+              Unexpected: true
+`)
+
+	var validationErrors ValidationErrors
+	assert.True(t, errors.As(err, &validationErrors))
+	assert.Len(t, validationErrors, 2)
+	assert.Equal(t, "$.Resources.MyTestResource.ForeignKeys[0].ResourceNmae", validationErrors[0].Path)
+	assert.Equal(t, 8, validationErrors[0].Line)
+	assert.Equal(t, `unknown field "ResourceNmae", did you mean "ResourceName"?`, validationErrors[0].Message)
+	assert.Equal(t, "$.Entities.MyTestEntity.Components.MyTestComponent1.Elements.ElementA.selectionCriteria", validationErrors[1].Path)
+	assert.Equal(t, `unknown field "selectionCriteria", did you mean "SelectionCriteria"?`, validationErrors[1].Message)
+}
+
+func TestStrictYmlParserAcceptsKnownFields(t *testing.T) {
+	ymlConfiguration, err := testConfigurations.ReadFile("configurations/entities_test.yml")
+	assert.Nil(t, err)
+
+	_, err = NewStrictYmlParser().Parse(string(ymlConfiguration))
+	assert.Nil(t, err)
+}
+
+func TestYmlParserIgnoresUnknownFieldsByDefault(t *testing.T) {
+	ymlSchema, err := NewYmlParser().Parse(`Name: Example
+Resources:
+  Orders:
+    TableName: orders
+    PrimaryKeys:
+      - orders.id
+`)
+
+	assert.Nil(t, err)
+	assert.Empty(t, ymlSchema.Resources["Orders"].PrimaryKey)
+}
+
+func TestLoaderRejectsUnknownFields(t *testing.T) {
+	_, err := LoadFile("../resources/imperfect_online_shop.yml")
+
+	var validationErrors ValidationErrors
+	assert.True(t, errors.As(err, &validationErrors))
+	assert.Equal(t, "imperfect_online_shop.yml", validationErrors[0].File)
+	assert.Contains(t, err.Error(), `imperfect_online_shop.yml:97:5: $.Resources.Orders.PrimaryKeys: unknown field "PrimaryKeys"`)
+}
@@ -0,0 +1,119 @@
+package extract
+
+import (
+	"context"
+	"fmt"
+
+	"entity-works/configuration"
+)
+
+type ParentRow struct {
+	Resource    string
+	Row         Row
+	Relation    string
+	Referrer    string
+	ReferrerKey string
+}
+
+func (parentRow ParentRow) String() string {
+	return fmt.Sprintf(
+		"%s row pulled in via %s from %s row %s",
+		parentRow.Resource,
+		parentRow.Relation,
+		parentRow.Referrer,
+		parentRow.ReferrerKey,
+	)
+}
+
+type parentReferences struct {
+	relation configuration.Relation
+	tuples   [][]any
+	reasons  map[string]ParentRow
+}
+
+func (extractor *Extractor) closeOverParentsOf(ctx context.Context, result *Result) error {
+	relationships := extractor.configuration.Relationships()
+	scanned := make(map[string]int)
+	for {
+		var pending []*parentReferences
+		pendingByRelation := make(map[string]*parentReferences)
+		for _, resourceName := range result.ResourceNames() {
+			resourceRows := result.resources[resourceName]
+			fromRelations := relationships.From(resourceName)
+			for _, relationName := range fromRelations.Names() {
+				relation := fromRelations[relationName]
+				known := result.referencedKeys(relation)
+				for _, row := range resourceRows.rows[scanned[resourceName]:] {
+					tuples, err := referencedTuples([]Row{row}, relation)
+					if err != nil {
+						return fmt.Errorf("expanding relation %q: %w", relation.QualifiedName(), err)
+					}
+
+					for _, tuple := range tuples {
+						key := tupleKey(tuple)
+						if known[key] {
+							continue
+						}
+
+						references, exists := pendingByRelation[relation.QualifiedName()]
+						if !exists {
+							references = &parentReferences{relation: relation, reasons: make(map[string]ParentRow)}
+							pendingByRelation[relation.QualifiedName()] = references
+							pending = append(pending, references)
+						}
+						if _, exists := references.reasons[key]; exists {
+							continue
+						}
+
+						references.tuples = append(references.tuples, tuple)
+						references.reasons[key] = ParentRow{
+							Resource:    relation.ToResource(),
+							Relation:    relation.QualifiedName(),
+							Referrer:    resourceName,
+							ReferrerKey: resourceRows.key(row),
+						}
+					}
+				}
+			}
+			scanned[resourceName] = resourceRows.Len()
+		}
+
+		if len(pending) == 0 {
+			return nil
+		}
+
+		for _, references := range pending {
+			if err := extractor.pullParents(ctx, result, references); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (extractor *Extractor) pullParents(ctx context.Context, result *Result, references *parentReferences) error {
+	relation := references.relation
+	resource, exists := extractor.configuration.Resource(relation.ToResource())
+	if !exists {
+		return &configuration.UnknownResourceError{Resource: relation.ToResource()}
+	}
+
+	resourceRows, exists := result.resources[resource.Name()]
+	if !exists {
+		resourceRows = NewResourceRows(resource)
+		result.resources[resource.Name()] = resourceRows
+	}
+
+	rowCount := resourceRows.Len()
+	if _, err := extractor.selectMatching(ctx, resourceRows, relation.ToKeys(), references.tuples); err != nil {
+		return fmt.Errorf("pulling parents via %q: %w", relation.QualifiedName(), err)
+	}
+
+	for _, row := range resourceRows.rows[rowCount:] {
+		key, _ := row.Key(relation.ToKeys())
+		parentRow := references.reasons[key]
+		parentRow.Row = row
+		result.parents = append(result.parents, parentRow)
+	}
+
+	return nil
+}
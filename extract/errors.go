@@ -0,0 +1,16 @@
+package extract
+
+import "fmt"
+
+type QueryError struct {
+	SQL string
+	Err error
+}
+
+func (queryError *QueryError) Error() string {
+	return fmt.Sprintf("running query %q: %s", queryError.SQL, queryError.Err)
+}
+
+func (queryError *QueryError) Unwrap() error {
+	return queryError.Err
+}
@@ -0,0 +1,195 @@
+package extract
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"entity-works/configuration"
+	"entity-works/sqlgen"
+)
+
+const keyBatchSize = 500
+
+type Extractor struct {
+	configuration    *configuration.Configuration
+	database         *sql.DB
+	generator        *sqlgen.Generator
+	closeOverParents bool
+}
+
+func NewExtractor(configuration *configuration.Configuration, database *sql.DB, dialect sqlgen.Dialect) *Extractor {
+	return &Extractor{
+		configuration: configuration,
+		database:      database,
+		generator:     sqlgen.NewGenerator(configuration, dialect),
+	}
+}
+
+func (extractor Extractor) WithCloseOverParents() *Extractor {
+	extractor.closeOverParents = true
+
+	return &extractor
+}
+
+func (extractor *Extractor) Extract(ctx context.Context, entityName string, parameters map[string]any) (*Result, error) {
+	entity, exists := extractor.configuration.Entity(entityName)
+	if !exists {
+		return nil, &sqlgen.UnknownEntityError{Entity: entityName}
+	}
+
+	values, err := entity.BindParameters(parameters)
+	if err != nil {
+		return nil, fmt.Errorf("binding parameters of entity %q: %w", entityName, err)
+	}
+
+	result := NewResult(entityName, values)
+	for _, elementPath := range extractor.configuration.DependencyGraph().Order() {
+		if elementPath.Entity != entityName {
+			continue
+		}
+
+		selected, err := extractor.selectElement(ctx, elementPath, values)
+		if err != nil {
+			return nil, fmt.Errorf("extracting element %q: %w", elementPath.String(), err)
+		}
+
+		result.addElement(elementPath, selected)
+	}
+
+	if extractor.closeOverParents {
+		if err := extractor.closeOverParentsOf(ctx, result); err != nil {
+			return nil, fmt.Errorf("closing over parents: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+func (extractor *Extractor) selectElement(
+	ctx context.Context,
+	elementPath configuration.ElementPath,
+	values map[string]any,
+) (*ResourceRows, error) {
+	element, exists := extractor.configuration.Element(elementPath)
+	if !exists {
+		return nil, &configuration.UnknownElementError{Element: elementPath.String()}
+	}
+
+	query, err := extractor.generator.GenerateElement(elementPath, values)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := NewResourceRows(element.Resource())
+	if _, err := extractor.selectRows(ctx, selected, query); err != nil {
+		return nil, err
+	}
+
+	return selected, nil
+}
+
+func (extractor *Extractor) selectMatching(
+	ctx context.Context,
+	selected *ResourceRows,
+	columns []configuration.ColumnRef,
+	tuples [][]any,
+) ([]Row, error) {
+	var selectedRows []Row
+	for _, batch := range batches(uniqueTuples(tuples)) {
+		rows, err := extractor.selectRows(ctx, selected, extractor.generator.GenerateKeyLookup(selected.resource, columns, batch))
+		if err != nil {
+			return nil, err
+		}
+		selectedRows = append(selectedRows, rows...)
+	}
+
+	return selectedRows, nil
+}
+
+func (extractor *Extractor) selectRows(ctx context.Context, selected *ResourceRows, query sqlgen.Query) ([]Row, error) {
+	sqlRows, err := extractor.database.QueryContext(ctx, query.SQL, query.Args...)
+	if err != nil {
+		return nil, &QueryError{SQL: query.SQL, Err: err}
+	}
+	defer sqlRows.Close()
+
+	columns, err := sqlRows.Columns()
+	if err != nil {
+		return nil, &QueryError{SQL: query.SQL, Err: err}
+	}
+
+	var rows []Row
+	for sqlRows.Next() {
+		values := make([]any, len(columns))
+		destinations := make([]any, len(columns))
+		for index := range values {
+			destinations[index] = &values[index]
+		}
+		if err := sqlRows.Scan(destinations...); err != nil {
+			return nil, &QueryError{SQL: query.SQL, Err: err}
+		}
+
+		row := make(Row, len(columns))
+		for index, column := range columns {
+			row[column] = values[index]
+		}
+
+		selected.Add(columns, row)
+		rows = append(rows, row)
+	}
+	if err := sqlRows.Err(); err != nil {
+		return nil, &QueryError{SQL: query.SQL, Err: err}
+	}
+
+	return rows, nil
+}
+
+func referencedTuples(rows []Row, relation configuration.Relation) ([][]any, error) {
+	var tuples [][]any
+	for _, row := range rows {
+		values, complete := row.Values(relation.FromKeys())
+		if !complete {
+			continue
+		}
+
+		if _, isDelimited := relation.DelimitedKey(); !isDelimited {
+			tuples = append(tuples, values)
+			continue
+		}
+
+		keys, err := relation.ExpandFromKey(FormatKeyValue(values[0]))
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			tuples = append(tuples, []any{key})
+		}
+	}
+
+	return tuples, nil
+}
+
+func uniqueTuples(tuples [][]any) [][]any {
+	seen := make(map[string]bool)
+	var unique [][]any
+	for _, tuple := range tuples {
+		key := tupleKey(tuple)
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, tuple)
+		}
+	}
+
+	return unique
+}
+
+func batches(tuples [][]any) [][][]any {
+	var batched [][][]any
+	for start := 0; start < len(tuples); start += keyBatchSize {
+		end := min(start+keyBatchSize, len(tuples))
+		batched = append(batched, tuples[start:end])
+	}
+
+	return batched
+}
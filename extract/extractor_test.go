@@ -0,0 +1,304 @@
+package extract
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"entity-works/configuration"
+	"entity-works/internal/testdb"
+	"entity-works/sqlgen"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const shopSchema = `
+CREATE TABLE regions (region TEXT PRIMARY KEY, name TEXT);
+CREATE TABLE customers (id INTEGER PRIMARY KEY, region TEXT, name TEXT);
+CREATE TABLE orders (id INTEGER PRIMARY KEY, customer_id INTEGER, total INTEGER);
+CREATE TABLE categories (id INTEGER PRIMARY KEY, parent_id INTEGER, name TEXT);
+CREATE TABLE customer_preferences (customer_id INTEGER PRIMARY KEY, fav_category_ids TEXT);
+
+INSERT INTO regions VALUES ('EU', 'Europe'), ('US', 'United States');
+INSERT INTO customers VALUES (1, 'EU', 'Ada'), (2, 'EU', 'Grace'), (3, 'US', 'Linus');
+INSERT INTO orders VALUES (10, 1, 50), (11, 1, 150), (12, 2, 20), (13, 3, 90);
+INSERT INTO categories VALUES (1, NULL, 'All'), (2, 1, 'Books'), (3, 2, 'Fiction'), (4, 3, 'Crime'), (5, 1, 'Music');
+INSERT INTO customer_preferences VALUES (1, '4, 5'), (2, '3'), (3, '5');
+`
+
+const shopYmlConfiguration = `Name: Shop
+Resources:
+  Regions:
+    TableName: regions
+    PrimaryKey:
+      - regions.region
+  Customers:
+    TableName: customers
+    PrimaryKey:
+      - customers.id
+    Index:
+      Region:
+        - customers.region
+  Orders:
+    TableName: orders
+    PrimaryKey:
+      - orders.id
+    ForeignKeys:
+      - Type: NORMAL
+        Key: orders.customer_id
+        ResourceName: Customers
+        ForeignKey: customers.id
+  Categories:
+    TableName: categories
+    PrimaryKey:
+      - categories.id
+    ForeignKeys:
+      - Type: NORMAL
+        Name: Parent
+        Key: categories.parent_id
+        ResourceName: Categories
+        ForeignKey: categories.id
+  CustomerPreferences:
+    TableName: customer_preferences
+    PrimaryKey:
+      - customer_preferences.customer_id
+    ForeignKeys:
+      - Type: NORMAL
+        Key: customer_preferences.customer_id
+        ResourceName: Customers
+        ForeignKey: customers.id
+      - Type: DELIMITED
+        Trim: true
+        Key: customer_preferences.fav_category_ids
+        ResourceName: Categories
+        ForeignKey: categories.id
+Entities:
+  CoreProduct:
+    Parameters:
+      region:
+        Required: true
+    Components:
+      Customers:
+        Elements:
+          Regions:
+            Resource: Regions
+            SelectionCriteria:
+              Type: Custom
+              Criteria: region = '{{region}}'
+          Customers:
+            Resource: Customers
+            SelectionCriteria:
+              Type: Index
+              Index: Region
+              Elements:
+                - Regions
+          Preferences:
+            Resource: CustomerPreferences
+            SelectionCriteria:
+              Type: Related
+              Elements:
+                - Customers
+          Categories:
+            Resource: Categories
+            SelectionCriteria:
+              Type: Related
+              Elements:
+                - Preferences
+              Recursive:
+                Direction: Ancestors
+      Orders:
+        Elements:
+          Customers:
+            Shares: CoreProduct::Customers::Customers
+          Orders:
+            Resource: Orders
+            SelectionCriteria:
+              Type: Related
+              Elements:
+                - Customers
+          BigOrders:
+            Resource: Orders
+            SelectionCriteria:
+              Type: Custom
+              Criteria: total > 100
+`
+
+func newShopExtractor(t *testing.T, ymlConfiguration string) *Extractor {
+	loadedConfiguration, err := configuration.LoadReader(strings.NewReader(ymlConfiguration))
+	assert.Nil(t, err)
+
+	return NewExtractor(loadedConfiguration, testdb.Open(t, shopSchema), sqlgen.NewSQLiteDialect())
+}
+
+func resourceKeys(t *testing.T, result *Result, resourceName string, column string) []string {
+	resourceRows, exists := result.Resource(resourceName)
+	assert.True(t, exists, resourceName)
+	if !exists {
+		return nil
+	}
+
+	var keys []string
+	for _, row := range resourceRows.Rows() {
+		keys = append(keys, FormatKeyValue(row[column]))
+	}
+
+	return keys
+}
+
+func TestExtractorCollectsRowsPerResourceInDependencyOrder(t *testing.T) {
+	result, err := newShopExtractor(t, shopYmlConfiguration).Extract(context.Background(), "CoreProduct", map[string]any{"region": "EU"})
+	assert.Nil(t, err)
+
+	assert.Equal(t, "CoreProduct", result.Entity())
+	assert.Equal(t, map[string]any{"region": "EU"}, result.Parameters())
+	assert.Equal(t, []string{"Categories", "CustomerPreferences", "Customers", "Orders", "Regions"}, result.ResourceNames())
+	assert.Equal(t, []string{"EU"}, resourceKeys(t, result, "Regions", "region"))
+	assert.Equal(t, []string{"1", "2"}, resourceKeys(t, result, "Customers", "id"))
+	assert.Equal(t, []string{"11", "10", "12"}, resourceKeys(t, result, "Orders", "id"))
+	assert.Equal(t, []string{"1", "2"}, resourceKeys(t, result, "CustomerPreferences", "customer_id"))
+
+	customers, _ := result.Resource("Customers")
+	assert.Equal(t, []string{"id", "region", "name"}, customers.Columns())
+	assert.Equal(t, Row{"id": int64(1), "region": "EU", "name": "Ada"}, customers.Rows()[0])
+
+	bigOrders, exists := result.Element(configuration.ElementPath{Entity: "CoreProduct", Component: "Orders", Element: "BigOrders"})
+	assert.True(t, exists)
+	assert.Equal(t, 1, bigOrders.Len())
+}
+
+func TestExtractorExpandsDelimitedKeysAndRecursion(t *testing.T) {
+	result, err := newShopExtractor(t, shopYmlConfiguration).Extract(context.Background(), "CoreProduct", map[string]any{"region": "EU"})
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, resourceKeys(t, result, "Categories", "id"))
+}
+
+func TestExtractorMatchesDelimitedListsHeldByTheElement(t *testing.T) {
+	result, err := newShopExtractor(t, shopYmlConfiguration+`  Music:
+    Components:
+      Fans:
+        Elements:
+          Music:
+            Resource: Categories
+            SelectionCriteria:
+              Type: Custom
+              Criteria: name = 'Music'
+          Preferences:
+            Resource: CustomerPreferences
+            SelectionCriteria:
+              Type: Related
+              Via: Categories
+              Elements:
+                - Music
+          Descendants:
+            Resource: Categories
+            SelectionCriteria:
+              Type: Custom
+              Criteria: id = 2
+              Recursive:
+                Direction: Descendants
+                MaxDepth: 1
+`).Extract(context.Background(), "Music", nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"1", "3"}, resourceKeys(t, result, "CustomerPreferences", "customer_id"))
+	assert.Equal(t, []string{"2", "3", "5"}, resourceKeys(t, result, "Categories", "id"))
+}
+
+func TestExtractorReportsEntityParameterAndQueryErrors(t *testing.T) {
+	extractor := newShopExtractor(t, shopYmlConfiguration)
+
+	_, err := extractor.Extract(context.Background(), "Reporting", nil)
+	var unknownEntityError *sqlgen.UnknownEntityError
+	assert.True(t, errors.As(err, &unknownEntityError))
+
+	_, err = extractor.Extract(context.Background(), "CoreProduct", nil)
+	var missingParameterError *configuration.MissingParameterError
+	assert.True(t, errors.As(err, &missingParameterError))
+
+	_, err = extractor.database.Exec("DROP TABLE orders")
+	assert.Nil(t, err)
+	_, err = extractor.Extract(context.Background(), "CoreProduct", map[string]any{"region": "EU"})
+	var queryError *QueryError
+	assert.True(t, errors.As(err, &queryError))
+	assert.Contains(t, err.Error(), `extracting element "CoreProduct::Orders::BigOrders"`)
+}
+
+func TestExtractorClosesOverParentsWhenAsked(t *testing.T) {
+	extractor := newShopExtractor(t, shopYmlConfiguration+`  Preferences:
+    Components:
+      Preferences:
+        Elements:
+          Preferences:
+            Resource: CustomerPreferences
+            SelectionCriteria:
+              Type: Custom
+              Criteria: customer_id = 1
+`)
+
+	result, err := extractor.Extract(context.Background(), "Preferences", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"CustomerPreferences"}, result.ResourceNames())
+	assert.Empty(t, result.Parents())
+
+	result, err = extractor.WithCloseOverParents().Extract(context.Background(), "Preferences", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Categories", "CustomerPreferences", "Customers"}, result.ResourceNames())
+	assert.Equal(t, []string{"4", "5", "1", "3", "2"}, resourceKeys(t, result, "Categories", "id"))
+	assert.Equal(t, []string{"1"}, resourceKeys(t, result, "Customers", "id"))
+
+	var reasons []string
+	for _, parentRow := range result.Parents() {
+		reasons = append(reasons, parentRow.String())
+	}
+	assert.Equal(
+		t,
+		[]string{
+			"Categories row pulled in via CustomerPreferences::Categories from CustomerPreferences row 1",
+			"Categories row pulled in via CustomerPreferences::Categories from CustomerPreferences row 1",
+			"Customers row pulled in via CustomerPreferences::Customers from CustomerPreferences row 1",
+			"Categories row pulled in via Categories::Parent from Categories row 5",
+			"Categories row pulled in via Categories::Parent from Categories row 4",
+			"Categories row pulled in via Categories::Parent from Categories row 3",
+		},
+		reasons,
+	)
+	assert.Equal(t, int64(1), result.Parents()[3].Row["id"])
+}
+
+func TestExtractorBindsParametersOfSharedElements(t *testing.T) {
+	const reportingEntity = `  Reporting:
+    Components:
+      Regions:
+        Elements:
+          Regions:
+            Shares: CoreProduct::Customers::Regions
+`
+	const reportingEntityWithRegion = `  Reporting:
+    Parameters:
+      region:
+        Required: true
+    Components:
+      Regions:
+        Elements:
+          Regions:
+            Shares: CoreProduct::Customers::Regions
+`
+	withDefault := strings.Replace(shopYmlConfiguration, "        Required: true\n", "        Default: EU\n", 1)
+
+	extractor := newShopExtractor(t, strings.Replace(withDefault, "\nEntities:\n", "\nEntities:\n"+reportingEntityWithRegion, 1))
+	result, err := extractor.Extract(context.Background(), "Reporting", map[string]any{"region": "US"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"US"}, resourceKeys(t, result, "Regions", "region"))
+
+	extractor = newShopExtractor(t, strings.Replace(withDefault, "\nEntities:\n", "\nEntities:\n"+reportingEntity, 1))
+	result, err = extractor.Extract(context.Background(), "Reporting", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"EU"}, resourceKeys(t, result, "Regions", "region"))
+
+	extractor = newShopExtractor(t, strings.Replace(shopYmlConfiguration, "\nEntities:\n", "\nEntities:\n"+reportingEntity, 1))
+	_, err = extractor.Extract(context.Background(), "Reporting", nil)
+	var unboundSharedParameterError *sqlgen.UnboundSharedParameterError
+	assert.True(t, errors.As(err, &unboundSharedParameterError))
+}
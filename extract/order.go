@@ -0,0 +1,72 @@
+package extract
+
+import "entity-works/configuration"
+
+func (resourceRows ResourceRows) DependencyOrderedRows(relationships configuration.Relationships) ([]Row, error) {
+	rows := resourceRows.Rows()
+	resourceName := resourceRows.Resource().Name()
+	fromRelations := relationships.From(resourceName)
+
+	var selfRelations []configuration.Relation
+	for _, relationName := range fromRelations.Names() {
+		if relation := fromRelations[relationName]; relation.ToResource() == resourceName {
+			selfRelations = append(selfRelations, relation)
+		}
+	}
+	if len(selfRelations) == 0 {
+		return rows, nil
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	rowIndexes := make([]map[string][]int, len(selfRelations))
+	for relationIndex, relation := range selfRelations {
+		rowIndexes[relationIndex] = make(map[string][]int)
+		for rowIndex, row := range rows {
+			if key, complete := row.Key(relation.ToKeys()); complete {
+				rowIndexes[relationIndex][key] = append(rowIndexes[relationIndex][key], rowIndex)
+			}
+		}
+	}
+
+	states := make([]int, len(rows))
+	ordered := make([]Row, 0, len(rows))
+	var visit func(rowIndex int) error
+	visit = func(rowIndex int) error {
+		if states[rowIndex] != unvisited {
+			return nil
+		}
+
+		states[rowIndex] = visiting
+		for relationIndex, relation := range selfRelations {
+			parentTuples, err := referencedTuples(rows[rowIndex:rowIndex+1], relation)
+			if err != nil {
+				return err
+			}
+
+			for _, parentTuple := range parentTuples {
+				for _, parentIndex := range rowIndexes[relationIndex][tupleKey(parentTuple)] {
+					if err := visit(parentIndex); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		states[rowIndex] = visited
+		ordered = append(ordered, rows[rowIndex])
+
+		return nil
+	}
+
+	for rowIndex := range rows {
+		if err := visit(rowIndex); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
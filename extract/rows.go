@@ -0,0 +1,240 @@
+package extract
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"entity-works/configuration"
+)
+
+const keySeparator = "\x1f"
+
+type Row map[string]any
+
+func (row Row) Values(columns []configuration.ColumnRef) ([]any, bool) {
+	values := make([]any, 0, len(columns))
+	for _, column := range columns {
+		value := row[column.Column]
+		if value == nil {
+			return nil, false
+		}
+
+		values = append(values, value)
+	}
+
+	return values, true
+}
+
+func (row Row) Key(columns []configuration.ColumnRef) (string, bool) {
+	values, complete := row.Values(columns)
+	if !complete {
+		return "", false
+	}
+
+	return tupleKey(values), true
+}
+
+func tupleKey(values []any) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		parts = append(parts, FormatKeyValue(value))
+	}
+
+	return strings.Join(parts, keySeparator)
+}
+
+func FormatKeyValue(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case []byte:
+		return string(value)
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	}
+
+	return fmt.Sprint(value)
+}
+
+type ResourceRows struct {
+	resource configuration.Resource
+	columns  []string
+	rows     []Row
+	keys     map[string]int
+}
+
+func NewResourceRows(resource configuration.Resource) *ResourceRows {
+	return &ResourceRows{
+		resource: resource,
+		keys:     make(map[string]int),
+	}
+}
+
+func (resourceRows ResourceRows) Resource() configuration.Resource {
+	return resourceRows.resource
+}
+
+func (resourceRows ResourceRows) Columns() []string {
+	return append([]string(nil), resourceRows.columns...)
+}
+
+func (resourceRows ResourceRows) Rows() []Row {
+	return append([]Row(nil), resourceRows.rows...)
+}
+
+func (resourceRows ResourceRows) Len() int {
+	return len(resourceRows.rows)
+}
+
+func (resourceRows ResourceRows) Row(key string) (Row, bool) {
+	index, exists := resourceRows.keys[key]
+	if !exists {
+		return nil, false
+	}
+
+	return resourceRows.rows[index], true
+}
+
+func (resourceRows ResourceRows) Contains(row Row) bool {
+	_, exists := resourceRows.keys[resourceRows.key(row)]
+
+	return exists
+}
+
+func (resourceRows *ResourceRows) Add(columns []string, row Row) bool {
+	if resourceRows.columns == nil {
+		resourceRows.columns = append([]string(nil), columns...)
+	}
+
+	key := resourceRows.key(row)
+	if _, exists := resourceRows.keys[key]; exists {
+		return false
+	}
+
+	resourceRows.keys[key] = len(resourceRows.rows)
+	resourceRows.rows = append(resourceRows.rows, row)
+
+	return true
+}
+
+func (resourceRows *ResourceRows) merge(other *ResourceRows) {
+	for _, row := range other.rows {
+		resourceRows.Add(other.columns, row)
+	}
+}
+
+func (resourceRows ResourceRows) key(row Row) string {
+	if primaryKey := resourceRows.resource.PrimaryKey(); len(primaryKey) > 0 {
+		values := make([]any, 0, len(primaryKey))
+		for _, column := range primaryKey {
+			values = append(values, row[column.Column])
+		}
+
+		return tupleKey(values)
+	}
+
+	values := make([]any, 0, len(resourceRows.columns))
+	for _, column := range resourceRows.columns {
+		values = append(values, row[column])
+	}
+
+	return tupleKey(values)
+}
+
+type Result struct {
+	entity     string
+	parameters map[string]any
+	resources  map[string]*ResourceRows
+	elements   map[configuration.ElementPath]*ResourceRows
+	parents    []ParentRow
+}
+
+func NewResult(entity string, parameters map[string]any) *Result {
+	return &Result{
+		entity:     entity,
+		parameters: parameters,
+		resources:  make(map[string]*ResourceRows),
+		elements:   make(map[configuration.ElementPath]*ResourceRows),
+	}
+}
+
+func (result Result) Entity() string {
+	return result.entity
+}
+
+func (result Result) Parameters() map[string]any {
+	parameters := make(map[string]any, len(result.parameters))
+	for name, value := range result.parameters {
+		parameters[name] = value
+	}
+
+	return parameters
+}
+
+func (result Result) ResourceNames() []string {
+	resourceNames := make([]string, 0, len(result.resources))
+	for resourceName := range result.resources {
+		resourceNames = append(resourceNames, resourceName)
+	}
+	sort.Strings(resourceNames)
+
+	return resourceNames
+}
+
+func (result Result) Resource(resourceName string) (*ResourceRows, bool) {
+	resourceRows, exists := result.resources[resourceName]
+
+	return resourceRows, exists
+}
+
+func (result Result) Element(elementPath configuration.ElementPath) (*ResourceRows, bool) {
+	resourceRows, exists := result.elements[elementPath]
+
+	return resourceRows, exists
+}
+
+func (result Result) Parents() []ParentRow {
+	return append([]ParentRow(nil), result.parents...)
+}
+
+func (result Result) referencedKeys(relation configuration.Relation) map[string]bool {
+	referencedKeys := make(map[string]bool)
+	if resourceRows, exists := result.resources[relation.ToResource()]; exists {
+		for _, row := range resourceRows.rows {
+			if key, complete := row.Key(relation.ToKeys()); complete {
+				referencedKeys[key] = true
+			}
+		}
+	}
+
+	return referencedKeys
+}
+
+func (result *Result) AddRows(resource configuration.Resource, columns []string, rows []Row) {
+	resourceRows, exists := result.resources[resource.Name()]
+	if !exists {
+		resourceRows = NewResourceRows(resource)
+		result.resources[resource.Name()] = resourceRows
+	}
+
+	for _, row := range rows {
+		resourceRows.Add(columns, row)
+	}
+}
+
+func (result *Result) addElement(elementPath configuration.ElementPath, selected *ResourceRows) {
+	result.elements[elementPath] = selected
+
+	resourceName := selected.resource.Name()
+	resourceRows, exists := result.resources[resourceName]
+	if !exists {
+		resourceRows = NewResourceRows(selected.resource)
+		result.resources[resourceName] = resourceRows
+	}
+
+	resourceRows.merge(selected)
+}
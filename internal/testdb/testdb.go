@@ -0,0 +1,23 @@
+package testdb
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	_ "modernc.org/sqlite"
+)
+
+func Open(t testing.TB, statements string) *sql.DB {
+	t.Helper()
+
+	database, err := sql.Open("sqlite", "file::memory:?_pragma=foreign_keys(1)")
+	assert.Nil(t, err)
+	database.SetMaxOpenConns(1)
+	t.Cleanup(func() { database.Close() })
+
+	_, err = database.Exec(statements)
+	assert.Nil(t, err)
+
+	return database
+}
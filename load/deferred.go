@@ -0,0 +1,116 @@
+package load
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"entity-works/configuration"
+	"entity-works/extract"
+	"entity-works/sqlgen"
+)
+
+func detachDeferredKeys(resource configuration.Resource, rows []extract.Row, deferredRelations []configuration.Relation) []extract.Row {
+	var columns []string
+	for _, relation := range deferredRelations {
+		if relation.FromResource() != resource.Name() {
+			continue
+		}
+		for _, fromKey := range relation.FromKeys() {
+			columns = append(columns, fromKey.Column)
+		}
+	}
+	if len(columns) == 0 {
+		return rows
+	}
+
+	detached := make([]extract.Row, 0, len(rows))
+	for _, row := range rows {
+		detachedRow := make(extract.Row, len(row))
+		for column, value := range row {
+			detachedRow[column] = value
+		}
+		for _, column := range columns {
+			detachedRow[column] = nil
+		}
+		detached = append(detached, detachedRow)
+	}
+
+	return detached
+}
+
+func (loader *Loader) linkDeferredRows(
+	ctx context.Context,
+	transaction *sql.Tx,
+	resourceRows *extract.ResourceRows,
+	relation configuration.Relation,
+	report *Report,
+) error {
+	resource := resourceRows.Resource()
+	primaryKey := resource.PrimaryKey()
+	if len(primaryKey) == 0 {
+		return &DeferredRelationError{Resource: resource.Name(), Relation: relation.QualifiedName()}
+	}
+
+	keyColumn, remapped := loader.remappedKeyColumn(resource)
+	for _, row := range resourceRows.Rows() {
+		if _, complete := row.Values(relation.FromKeys()); !complete {
+			continue
+		}
+
+		linked := make(extract.Row, len(relation.FromKeys()))
+		for _, fromKey := range relation.FromKeys() {
+			linked[fromKey.Column] = row[fromKey.Column]
+		}
+		linked, err := loader.rewriteReferences(resource, linked, report)
+		if err != nil {
+			return err
+		}
+
+		keys := make(extract.Row, len(primaryKey))
+		for _, column := range primaryKey {
+			keys[column.Column] = row[column.Column]
+		}
+		if remapped {
+			newKey, exists := report.remappedKeys[resource.Name()][extract.FormatKeyValue(row[keyColumn])]
+			if !exists {
+				return &UnmappedReferenceError{Resource: resource.Name(), Relation: relation.QualifiedName(), Value: extract.FormatKeyValue(row[keyColumn])}
+			}
+			keys[keyColumn] = newKey
+		}
+
+		statement, args := loader.updateStatement(resource, relation.FromKeys(), linked, primaryKey, keys)
+		if _, err := transaction.ExecContext(ctx, statement, args...); err != nil {
+			return &InsertError{Resource: resource.Name(), SQL: statement, Err: err}
+		}
+	}
+
+	return nil
+}
+
+func (loader *Loader) updateStatement(
+	resource configuration.Resource,
+	columns []configuration.ColumnRef,
+	values extract.Row,
+	keyColumns []configuration.ColumnRef,
+	keys extract.Row,
+) (string, []any) {
+	var args []any
+	assignments := make([]string, 0, len(columns))
+	conditions := make([]string, 0, len(keyColumns)+len(columns))
+	for _, column := range columns {
+		args = append(args, values[column.Column])
+		assignments = append(assignments, loader.dialect.QuoteIdentifier(column.Column)+" = "+loader.dialect.Placeholder(len(args)))
+	}
+	for _, column := range keyColumns {
+		args = append(args, keys[column.Column])
+		conditions = append(conditions, loader.dialect.QuoteIdentifier(column.Column)+" = "+loader.dialect.Placeholder(len(args)))
+	}
+	for _, column := range columns {
+		conditions = append(conditions, loader.dialect.QuoteIdentifier(column.Column)+" IS NULL")
+	}
+
+	return "UPDATE " + sqlgen.QuoteTable(loader.dialect, resource.Table()) +
+		" SET " + strings.Join(assignments, ", ") +
+		" WHERE " + strings.Join(conditions, " AND "), args
+}
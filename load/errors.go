@@ -0,0 +1,73 @@
+package load
+
+import "fmt"
+
+type InsertError struct {
+	Resource string
+	SQL      string
+	Err      error
+}
+
+func (insertError *InsertError) Error() string {
+	return fmt.Sprintf("inserting into resource %q with %q: %s", insertError.Resource, insertError.SQL, insertError.Err)
+}
+
+func (insertError *InsertError) Unwrap() error {
+	return insertError.Err
+}
+
+type UnmappedReferenceError struct {
+	Resource string
+	Relation string
+	Value    string
+}
+
+func (unmappedReferenceError *UnmappedReferenceError) Error() string {
+	return fmt.Sprintf(
+		"relation %q of resource %q references key %q, which was not remapped by this load",
+		unmappedReferenceError.Relation,
+		unmappedReferenceError.Resource,
+		unmappedReferenceError.Value,
+	)
+}
+
+type CompositeRemappedReferenceError struct {
+	Resource           string
+	Relation           string
+	ReferencedResource string
+}
+
+func (compositeRemappedReferenceError *CompositeRemappedReferenceError) Error() string {
+	return fmt.Sprintf(
+		"relation %q of resource %q references the remapped key of resource %q through a composite foreign key, which cannot be remapped",
+		compositeRemappedReferenceError.Relation,
+		compositeRemappedReferenceError.Resource,
+		compositeRemappedReferenceError.ReferencedResource,
+	)
+}
+
+type DeferredRelationError struct {
+	Resource string
+	Relation string
+}
+
+func (deferredRelationError *DeferredRelationError) Error() string {
+	return fmt.Sprintf(
+		"relation %q of resource %q closes a dependency cycle, but the resource has no primary key to link its rows by",
+		deferredRelationError.Relation,
+		deferredRelationError.Resource,
+	)
+}
+
+type RemappingOptionError struct {
+	Resource string
+	Option   string
+}
+
+func (remappingOptionError *RemappingOptionError) Error() string {
+	return fmt.Sprintf(
+		"resource %q is remapped one row at a time and failing on conflicts, so it cannot be loaded with %s",
+		remappingOptionError.Resource,
+		remappingOptionError.Option,
+	)
+}
@@ -0,0 +1,215 @@
+package load
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"entity-works/configuration"
+	"entity-works/extract"
+	"entity-works/sqlgen"
+)
+
+const defaultBatchSize = 100
+
+type Report struct {
+	resourceNames []string
+	rowsAffected  map[string]int64
+	remappedKeys  map[string]map[string]any
+}
+
+func (report Report) ResourceNames() []string {
+	return append([]string(nil), report.resourceNames...)
+}
+
+func (report Report) RowsAffected(resourceName string) int64 {
+	return report.rowsAffected[resourceName]
+}
+
+func (report Report) RemappedKeys(resourceName string) map[string]any {
+	remappedKeys := make(map[string]any, len(report.remappedKeys[resourceName]))
+	for oldKey, newKey := range report.remappedKeys[resourceName] {
+		remappedKeys[oldKey] = newKey
+	}
+
+	return remappedKeys
+}
+
+func (report Report) RemappedKey(resourceName string, oldKey any) (any, bool) {
+	newKey, exists := report.remappedKeys[resourceName][extract.FormatKeyValue(oldKey)]
+
+	return newKey, exists
+}
+
+type Loader struct {
+	configuration      *configuration.Configuration
+	database           *sql.DB
+	dialect            sqlgen.Dialect
+	batchSize          int
+	conflictPolicy     sqlgen.ConflictPolicy
+	remapAutoIncrement bool
+}
+
+func NewLoader(configuration *configuration.Configuration, database *sql.DB, dialect sqlgen.Dialect) *Loader {
+	return &Loader{
+		configuration:  configuration,
+		database:       database,
+		dialect:        dialect,
+		conflictPolicy: sqlgen.FailOnConflict,
+	}
+}
+
+func (loader Loader) WithBatchSize(batchSize int) *Loader {
+	loader.batchSize = batchSize
+
+	return &loader
+}
+
+func (loader Loader) WithConflictPolicy(conflictPolicy sqlgen.ConflictPolicy) *Loader {
+	loader.conflictPolicy = conflictPolicy
+
+	return &loader
+}
+
+func (loader Loader) WithAutoIncrementRemapping() *Loader {
+	loader.remapAutoIncrement = true
+
+	return &loader
+}
+
+func (loader *Loader) Load(ctx context.Context, result *extract.Result) (report *Report, err error) {
+	resourceNames, deferredRelations := loader.configuration.Relationships().DeferredDependencyOrder(result.ResourceNames())
+	if err := loader.checkRemapping(resourceNames); err != nil {
+		return nil, err
+	}
+
+	transaction, err := loader.database.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			transaction.Rollback()
+		}
+	}()
+
+	report = &Report{
+		resourceNames: resourceNames,
+		rowsAffected:  make(map[string]int64),
+		remappedKeys:  make(map[string]map[string]any),
+	}
+	for _, resourceName := range resourceNames {
+		resourceRows, _ := result.Resource(resourceName)
+		rowsAffected, err := loader.insertRows(ctx, transaction, resourceRows, deferredRelations, report)
+		if err != nil {
+			return nil, err
+		}
+
+		report.rowsAffected[resourceName] = rowsAffected
+	}
+
+	for _, relation := range deferredRelations {
+		resourceRows, _ := result.Resource(relation.FromResource())
+		if err := loader.linkDeferredRows(ctx, transaction, resourceRows, relation, report); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return report, nil
+}
+
+func (loader *Loader) insertRows(
+	ctx context.Context,
+	transaction *sql.Tx,
+	resourceRows *extract.ResourceRows,
+	deferredRelations []configuration.Relation,
+	report *Report,
+) (int64, error) {
+	resource := resourceRows.Resource()
+	rows, err := resourceRows.DependencyOrderedRows(loader.configuration.Relationships())
+	if err != nil {
+		return 0, fmt.Errorf("ordering rows of resource %q: %w", resource.Name(), err)
+	}
+	rows = detachDeferredKeys(resource, rows, deferredRelations)
+
+	if keyColumn, remapped := loader.remappedKeyColumn(resource); remapped {
+		return loader.insertRemappedRows(ctx, transaction, resource, resourceRows.Columns(), keyColumn, rows, report)
+	}
+
+	keyColumns := make([]string, 0, len(resource.PrimaryKey()))
+	for _, column := range resource.PrimaryKey() {
+		keyColumns = append(keyColumns, loader.dialect.QuoteIdentifier(column.Column))
+	}
+
+	batchSize := loader.batchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	var rowsAffected int64
+	for start := 0; start < len(rows); start += batchSize {
+		batch := rows[start:min(start+batchSize, len(rows))]
+		for index, row := range batch {
+			rewritten, err := loader.rewriteReferences(resource, row, report)
+			if err != nil {
+				return 0, err
+			}
+			batch[index] = rewritten
+		}
+
+		statement, args, err := loader.insertStatement(resource, resourceRows.Columns(), batch, loader.conflictPolicy, keyColumns)
+		if err != nil {
+			return 0, err
+		}
+
+		execResult, err := transaction.ExecContext(ctx, statement, args...)
+		if err != nil {
+			return 0, &InsertError{Resource: resource.Name(), SQL: statement, Err: err}
+		}
+
+		affected, err := execResult.RowsAffected()
+		if err != nil {
+			return 0, &InsertError{Resource: resource.Name(), SQL: statement, Err: err}
+		}
+		rowsAffected += affected
+	}
+
+	return rowsAffected, nil
+}
+
+func (loader *Loader) insertStatement(
+	resource configuration.Resource,
+	columns []string,
+	rows []extract.Row,
+	conflictPolicy sqlgen.ConflictPolicy,
+	keyColumns []string,
+) (string, []any, error) {
+	quotedColumns := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quotedColumns = append(quotedColumns, loader.dialect.QuoteIdentifier(column))
+	}
+
+	var args []any
+	values := make([]string, 0, len(rows))
+	for _, row := range rows {
+		placeholders := make([]string, 0, len(columns))
+		for _, column := range columns {
+			args = append(args, row[column])
+			placeholders = append(placeholders, loader.dialect.Placeholder(len(args)))
+		}
+		values = append(values, "("+strings.Join(placeholders, ", ")+")")
+	}
+
+	table := sqlgen.QuoteTable(loader.dialect, resource.Table())
+	statement, err := loader.dialect.Insert(table, quotedColumns, values, conflictPolicy, keyColumns)
+	if err != nil {
+		return "", nil, fmt.Errorf("inserting into resource %q: %w", resource.Name(), err)
+	}
+
+	return statement, args, nil
+}
@@ -0,0 +1,348 @@
+package load
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"entity-works/configuration"
+	"entity-works/extract"
+	"entity-works/internal/testdb"
+	"entity-works/sqlgen"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const shopSchema = `
+CREATE TABLE customers (id INTEGER PRIMARY KEY, name TEXT);
+CREATE TABLE orders (id INTEGER PRIMARY KEY, customer_id INTEGER NOT NULL REFERENCES customers (id), total INTEGER);
+CREATE TABLE categories (id INTEGER PRIMARY KEY, parent_id INTEGER REFERENCES categories (id), name TEXT);
+CREATE TABLE wishlists (id INTEGER PRIMARY KEY, customer_id INTEGER NOT NULL REFERENCES customers (id), category_ids TEXT);
+`
+
+const shopYmlConfiguration = `Name: Shop
+Resources:
+  Customers:
+    TableName: customers
+    AutoIncrement: true
+    PrimaryKey:
+      - customers.id
+  Orders:
+    TableName: orders
+    PrimaryKey:
+      - orders.id
+    ForeignKeys:
+      - Type: NORMAL
+        Key: orders.customer_id
+        ResourceName: Customers
+        ForeignKey: customers.id
+  Categories:
+    TableName: categories
+    AutoIncrement: true
+    PrimaryKey:
+      - categories.id
+    ForeignKeys:
+      - Type: NORMAL
+        Name: Parent
+        Key: categories.parent_id
+        ResourceName: Categories
+        ForeignKey: categories.id
+  Wishlists:
+    TableName: wishlists
+    PrimaryKey:
+      - wishlists.id
+    ForeignKeys:
+      - Type: NORMAL
+        Key: wishlists.customer_id
+        ResourceName: Customers
+        ForeignKey: customers.id
+      - Type: DELIMITED
+        Format: JSON
+        Key: wishlists.category_ids
+        ResourceName: Categories
+        ForeignKey: categories.id
+Entities: {}
+`
+
+func loadShopConfiguration(t *testing.T, ymlConfiguration string) *configuration.Configuration {
+	loadedConfiguration, err := configuration.LoadReader(strings.NewReader(ymlConfiguration))
+	assert.Nil(t, err)
+
+	return loadedConfiguration
+}
+
+func addRows(t *testing.T, loadedConfiguration *configuration.Configuration, result *extract.Result, resourceName string, columns []string, rows ...extract.Row) {
+	resource, exists := loadedConfiguration.Resource(resourceName)
+	assert.True(t, exists, resourceName)
+	result.AddRows(resource, columns, rows)
+}
+
+func shopResult(t *testing.T) (*configuration.Configuration, *extract.Result) {
+	loadedConfiguration := loadShopConfiguration(t, shopYmlConfiguration)
+
+	result := extract.NewResult("CoreProduct", nil)
+	addRows(
+		t, loadedConfiguration, result, "Customers", []string{"id", "name"},
+		extract.Row{"id": int64(1), "name": "Ada"},
+		extract.Row{"id": int64(2), "name": "Grace"},
+		extract.Row{"id": int64(3), "name": "Linus"},
+	)
+	addRows(
+		t, loadedConfiguration, result, "Orders", []string{"id", "customer_id", "total"},
+		extract.Row{"id": int64(10), "customer_id": int64(1), "total": int64(50)},
+		extract.Row{"id": int64(11), "customer_id": int64(2), "total": int64(150)},
+		extract.Row{"id": int64(12), "customer_id": int64(3), "total": int64(20)},
+	)
+	addRows(
+		t, loadedConfiguration, result, "Categories", []string{"id", "parent_id", "name"},
+		extract.Row{"id": int64(3), "parent_id": int64(2), "name": "Fiction"},
+		extract.Row{"id": int64(2), "parent_id": int64(1), "name": "Books"},
+		extract.Row{"id": int64(1), "parent_id": nil, "name": "All"},
+	)
+	addRows(
+		t, loadedConfiguration, result, "Wishlists", []string{"id", "customer_id", "category_ids"},
+		extract.Row{"id": int64(100), "customer_id": int64(1), "category_ids": "[3, 2]"},
+	)
+
+	return loadedConfiguration, result
+}
+
+func queryStrings(t *testing.T, database *sql.DB, query string) []string {
+	rows, err := database.Query(query)
+	assert.Nil(t, err)
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var value string
+		assert.Nil(t, rows.Scan(&value))
+		values = append(values, value)
+	}
+
+	return values
+}
+
+func TestLoaderInsertsParentsBeforeChildrenInBatches(t *testing.T) {
+	loadedConfiguration, result := shopResult(t)
+	target := testdb.Open(t, shopSchema)
+
+	report, err := NewLoader(loadedConfiguration, target, sqlgen.NewSQLiteDialect()).WithBatchSize(2).Load(context.Background(), result)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"Categories", "Customers", "Orders", "Wishlists"}, report.ResourceNames())
+	assert.Equal(t, int64(3), report.RowsAffected("Customers"))
+	assert.Equal(t, []string{"10:Ada", "11:Grace", "12:Linus"}, queryStrings(t, target, `
+SELECT orders.id || ':' || customers.name FROM orders JOIN customers ON customers.id = orders.customer_id ORDER BY orders.id`))
+	assert.Equal(t, []string{"All", "Books", "Fiction"}, queryStrings(t, target, "SELECT name FROM categories ORDER BY id"))
+}
+
+func TestLoaderAppliesConflictPolicies(t *testing.T) {
+	loadedConfiguration, result := shopResult(t)
+	const existing = "INSERT INTO customers VALUES (2, 'Existing');"
+
+	target := testdb.Open(t, shopSchema+existing)
+	_, err := NewLoader(loadedConfiguration, target, sqlgen.NewSQLiteDialect()).Load(context.Background(), result)
+	var insertError *InsertError
+	assert.True(t, errors.As(err, &insertError))
+	assert.Equal(t, "Customers", insertError.Resource)
+	assert.Equal(t, []string{"Existing"}, queryStrings(t, target, "SELECT name FROM customers"))
+	assert.Empty(t, queryStrings(t, target, "SELECT name FROM categories"))
+
+	target = testdb.Open(t, shopSchema+existing)
+	report, err := NewLoader(loadedConfiguration, target, sqlgen.NewSQLiteDialect()).
+		WithConflictPolicy(sqlgen.SkipOnConflict).
+		Load(context.Background(), result)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), report.RowsAffected("Customers"))
+	assert.Equal(t, []string{"Ada", "Existing", "Linus"}, queryStrings(t, target, "SELECT name FROM customers ORDER BY id"))
+
+	target = testdb.Open(t, shopSchema+existing)
+	report, err = NewLoader(loadedConfiguration, target, sqlgen.NewSQLiteDialect()).
+		WithConflictPolicy(sqlgen.UpsertOnConflict).
+		Load(context.Background(), result)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), report.RowsAffected("Customers"))
+	assert.Equal(t, []string{"Ada", "Grace", "Linus"}, queryStrings(t, target, "SELECT name FROM customers ORDER BY id"))
+}
+
+func TestLoaderRemapsAutoIncrementKeysAndTheirReferences(t *testing.T) {
+	loadedConfiguration, result := shopResult(t)
+	target := testdb.Open(t, shopSchema+`
+INSERT INTO customers VALUES (1, 'Existing'), (2, 'Existing');
+INSERT INTO categories VALUES (1, NULL, 'Existing');
+INSERT INTO wishlists VALUES (99, 1, '[1]');
+`)
+
+	report, err := NewLoader(loadedConfiguration, target, sqlgen.NewSQLiteDialect()).
+		WithAutoIncrementRemapping().
+		Load(context.Background(), result)
+	assert.Nil(t, err)
+
+	assert.Equal(t, map[string]any{"1": int64(3), "2": int64(4), "3": int64(5)}, report.RemappedKeys("Customers"))
+	newKey, remapped := report.RemappedKey("Categories", int64(3))
+	assert.True(t, remapped)
+	assert.Equal(t, int64(4), newKey)
+	assert.Empty(t, report.RemappedKeys("Orders"))
+
+	assert.Equal(t, []string{"10:Ada", "11:Grace", "12:Linus"}, queryStrings(t, target, `
+SELECT orders.id || ':' || customers.name FROM orders JOIN customers ON customers.id = orders.customer_id ORDER BY orders.id`))
+	assert.Equal(t, []string{"2:-", "3:2", "4:3"}, queryStrings(t, target, `
+SELECT id || ':' || COALESCE(parent_id, '-') FROM categories WHERE name <> 'Existing' ORDER BY id`))
+	assert.Equal(t, []string{"1[1]", "3[4,3]"}, queryStrings(t, target, "SELECT customer_id || category_ids FROM wishlists ORDER BY id"))
+}
+
+func TestLoaderRejectsReferencesToKeysItDidNotRemap(t *testing.T) {
+	loadedConfiguration := loadShopConfiguration(t, shopYmlConfiguration)
+	result := extract.NewResult("CoreProduct", nil)
+	addRows(
+		t, loadedConfiguration, result, "Orders", []string{"id", "customer_id", "total"},
+		extract.Row{"id": int64(10), "customer_id": int64(1), "total": int64(50)},
+	)
+
+	target := testdb.Open(t, shopSchema+"INSERT INTO customers VALUES (1, 'Existing');")
+	_, err := NewLoader(loadedConfiguration, target, sqlgen.NewSQLiteDialect()).
+		WithAutoIncrementRemapping().
+		Load(context.Background(), result)
+	var unmappedReferenceError *UnmappedReferenceError
+	assert.True(t, errors.As(err, &unmappedReferenceError))
+	assert.Equal(t, "Orders", unmappedReferenceError.Resource)
+	assert.Equal(t, "1", unmappedReferenceError.Value)
+	assert.Empty(t, queryStrings(t, target, "SELECT id FROM orders"))
+}
+
+func TestLoaderRejectsCompositeReferencesToRemappedKeys(t *testing.T) {
+	loadedConfiguration := loadShopConfiguration(t, strings.Replace(shopYmlConfiguration, `        Key: orders.customer_id
+        ResourceName: Customers
+        ForeignKey: customers.id
+`, `        Key:
+          - orders.customer_id
+          - orders.total
+        ResourceName: Customers
+        ForeignKey:
+          - customers.id
+          - customers.name
+`, 1))
+	result := extract.NewResult("CoreProduct", nil)
+	addRows(t, loadedConfiguration, result, "Customers", []string{"id", "name"}, extract.Row{"id": int64(1), "name": "Ada"})
+	addRows(
+		t, loadedConfiguration, result, "Orders", []string{"id", "customer_id", "total"},
+		extract.Row{"id": int64(10), "customer_id": int64(1), "total": int64(50)},
+	)
+
+	_, err := NewLoader(loadedConfiguration, testdb.Open(t, shopSchema), sqlgen.NewSQLiteDialect()).
+		WithAutoIncrementRemapping().
+		Load(context.Background(), result)
+	var compositeRemappedReferenceError *CompositeRemappedReferenceError
+	assert.True(t, errors.As(err, &compositeRemappedReferenceError))
+	assert.Equal(t, "Orders", compositeRemappedReferenceError.Resource)
+	assert.Equal(t, "Customers", compositeRemappedReferenceError.ReferencedResource)
+}
+
+func TestLoaderBreaksDependencyCyclesWithDeferredUpdates(t *testing.T) {
+	const addressBookSchema = `
+CREATE TABLE customers (id INTEGER PRIMARY KEY, name TEXT, default_address_id INTEGER REFERENCES addresses (id));
+CREATE TABLE addresses (id INTEGER PRIMARY KEY, customer_id INTEGER NOT NULL REFERENCES customers (id), street TEXT);
+`
+	loadedConfiguration := loadShopConfiguration(t, `Name: AddressBook
+Resources:
+  Customers:
+    TableName: customers
+    AutoIncrement: true
+    PrimaryKey:
+      - customers.id
+    ForeignKeys:
+      - Name: DefaultAddress
+        Type: NORMAL
+        Key: customers.default_address_id
+        ResourceName: Addresses
+        ForeignKey: addresses.id
+  Addresses:
+    TableName: addresses
+    AutoIncrement: true
+    PrimaryKey:
+      - addresses.id
+    ForeignKeys:
+      - Type: NORMAL
+        Key: addresses.customer_id
+        ResourceName: Customers
+        ForeignKey: customers.id
+Entities: {}
+`)
+	result := extract.NewResult("AddressBook", nil)
+	addRows(
+		t, loadedConfiguration, result, "Customers", []string{"id", "name", "default_address_id"},
+		extract.Row{"id": int64(1), "name": "Ada", "default_address_id": int64(1)},
+		extract.Row{"id": int64(2), "name": "Grace", "default_address_id": nil},
+	)
+	addRows(
+		t, loadedConfiguration, result, "Addresses", []string{"id", "customer_id", "street"},
+		extract.Row{"id": int64(1), "customer_id": int64(1), "street": "Main Street"},
+		extract.Row{"id": int64(2), "customer_id": int64(2), "street": "High Street"},
+	)
+	const defaultAddresses = `
+SELECT customers.name || ':' || COALESCE(addresses.street, '-') FROM customers
+LEFT JOIN addresses ON addresses.id = customers.default_address_id ORDER BY customers.id`
+
+	target := testdb.Open(t, addressBookSchema)
+	report, err := NewLoader(loadedConfiguration, target, sqlgen.NewSQLiteDialect()).Load(context.Background(), result)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Customers", "Addresses"}, report.ResourceNames())
+	assert.Equal(t, []string{"Ada:Main Street", "Grace:-"}, queryStrings(t, target, defaultAddresses))
+
+	target = testdb.Open(t, addressBookSchema+`
+INSERT INTO customers VALUES (1, 'Existing', NULL);
+INSERT INTO addresses VALUES (1, 1, 'Old Street');
+UPDATE customers SET default_address_id = 1;
+`)
+	report, err = NewLoader(loadedConfiguration, target, sqlgen.NewSQLiteDialect()).
+		WithAutoIncrementRemapping().
+		Load(context.Background(), result)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]any{"1": int64(2), "2": int64(3)}, report.RemappedKeys("Addresses"))
+	assert.Equal(t, []string{"Existing:Old Street", "Ada:Main Street", "Grace:-"}, queryStrings(t, target, defaultAddresses))
+}
+
+func TestLoaderRejectsOptionsItCannotApplyToRemappedResources(t *testing.T) {
+	loadedConfiguration, result := shopResult(t)
+
+	_, err := NewLoader(loadedConfiguration, testdb.Open(t, shopSchema), sqlgen.NewSQLiteDialect()).
+		WithAutoIncrementRemapping().
+		WithConflictPolicy(sqlgen.SkipOnConflict).
+		Load(context.Background(), result)
+	var remappingOptionError *RemappingOptionError
+	assert.True(t, errors.As(err, &remappingOptionError))
+	assert.Equal(t, "Categories", remappingOptionError.Resource)
+	assert.Equal(t, `conflict policy "skip"`, remappingOptionError.Option)
+
+	_, err = NewLoader(loadedConfiguration, testdb.Open(t, shopSchema), sqlgen.NewSQLiteDialect()).
+		WithAutoIncrementRemapping().
+		WithBatchSize(2).
+		Load(context.Background(), result)
+	assert.True(t, errors.As(err, &remappingOptionError))
+	assert.Equal(t, "batch size 2", remappingOptionError.Option)
+}
+
+func TestLoaderRemapsRowsThatOnlyHaveAKey(t *testing.T) {
+	const tagsSchema = "CREATE TABLE tags (id INTEGER PRIMARY KEY);"
+	loadedConfiguration := loadShopConfiguration(t, `Name: Tags
+Resources:
+  Tags:
+    TableName: tags
+    AutoIncrement: true
+    PrimaryKey:
+      - tags.id
+Entities: {}
+`)
+	result := extract.NewResult("Tags", nil)
+	addRows(t, loadedConfiguration, result, "Tags", []string{"id"}, extract.Row{"id": int64(1)}, extract.Row{"id": int64(2)})
+
+	target := testdb.Open(t, tagsSchema+"INSERT INTO tags VALUES (1);")
+	report, err := NewLoader(loadedConfiguration, target, sqlgen.NewSQLiteDialect()).
+		WithAutoIncrementRemapping().
+		Load(context.Background(), result)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]any{"1": int64(2), "2": int64(3)}, report.RemappedKeys("Tags"))
+	assert.Equal(t, []string{"1", "2", "3"}, queryStrings(t, target, "SELECT id FROM tags ORDER BY id"))
+}
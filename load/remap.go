@@ -0,0 +1,201 @@
+package load
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"entity-works/configuration"
+	"entity-works/extract"
+	"entity-works/sqlgen"
+)
+
+func (loader *Loader) remappedKeyColumn(resource configuration.Resource) (string, bool) {
+	primaryKey := resource.PrimaryKey()
+	if !loader.remapAutoIncrement || !resource.AutoIncrement() || len(primaryKey) != 1 {
+		return "", false
+	}
+
+	return primaryKey[0].Column, true
+}
+
+func (loader *Loader) insertRemappedRows(
+	ctx context.Context,
+	transaction *sql.Tx,
+	resource configuration.Resource,
+	columns []string,
+	keyColumn string,
+	rows []extract.Row,
+	report *Report,
+) (int64, error) {
+	var insertedColumns []string
+	for _, column := range columns {
+		if column != keyColumn {
+			insertedColumns = append(insertedColumns, column)
+		}
+	}
+
+	remappedKeys := make(map[string]any, len(rows))
+	report.remappedKeys[resource.Name()] = remappedKeys
+	for _, row := range rows {
+		rewritten, err := loader.rewriteReferences(resource, row, report)
+		if err != nil {
+			return 0, err
+		}
+
+		statement, args, err := loader.insertRemappedStatement(resource, insertedColumns, rewritten)
+		if err != nil {
+			return 0, err
+		}
+
+		var newKey any
+		returning := loader.dialect.Returning(loader.dialect.QuoteIdentifier(keyColumn))
+		if returning != "" {
+			statement += returning
+			err = transaction.QueryRowContext(ctx, statement, args...).Scan(&newKey)
+		} else {
+			var execResult sql.Result
+			execResult, err = transaction.ExecContext(ctx, statement, args...)
+			if err == nil {
+				newKey, err = execResult.LastInsertId()
+			}
+		}
+		if err != nil {
+			return 0, &InsertError{Resource: resource.Name(), SQL: statement, Err: err}
+		}
+
+		if oldKey := row[keyColumn]; oldKey != nil {
+			remappedKeys[extract.FormatKeyValue(oldKey)] = newKey
+		}
+	}
+
+	return int64(len(rows)), nil
+}
+
+func (loader *Loader) insertRemappedStatement(resource configuration.Resource, columns []string, row extract.Row) (string, []any, error) {
+	if len(columns) == 0 {
+		return loader.dialect.InsertDefaultValues(sqlgen.QuoteTable(loader.dialect, resource.Table())), nil, nil
+	}
+
+	return loader.insertStatement(resource, columns, []extract.Row{row}, sqlgen.FailOnConflict, nil)
+}
+
+func (loader *Loader) checkRemapping(resourceNames []string) error {
+	if !loader.remapAutoIncrement {
+		return nil
+	}
+
+	for _, resourceName := range resourceNames {
+		resource, exists := loader.configuration.Resource(resourceName)
+		if !exists {
+			continue
+		}
+		if _, remapped := loader.remappedKeyColumn(resource); !remapped {
+			continue
+		}
+
+		if loader.conflictPolicy != sqlgen.FailOnConflict {
+			return &RemappingOptionError{Resource: resourceName, Option: fmt.Sprintf("conflict policy %q", loader.conflictPolicy)}
+		}
+		if loader.batchSize > 0 {
+			return &RemappingOptionError{Resource: resourceName, Option: fmt.Sprintf("batch size %d", loader.batchSize)}
+		}
+	}
+
+	for _, resourceName := range resourceNames {
+		fromRelations := loader.configuration.Relationships().From(resourceName)
+		for _, relationName := range fromRelations.Names() {
+			relation := fromRelations[relationName]
+			if len(relation.FromKeys()) > 1 && loader.referencesRemappedKey(relation) {
+				return &CompositeRemappedReferenceError{
+					Resource:           resourceName,
+					Relation:           relation.QualifiedName(),
+					ReferencedResource: relation.ToResource(),
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (loader *Loader) rewriteReferences(resource configuration.Resource, row extract.Row, report *Report) (extract.Row, error) {
+	fromRelations := loader.configuration.Relationships().From(resource.Name())
+	rewritten := row
+	copied := false
+	for _, relationName := range fromRelations.Names() {
+		relation := fromRelations[relationName]
+		if !loader.referencesRemappedKey(relation) {
+			continue
+		}
+
+		column := relation.FromKeys()[0].Column
+		value := row[column]
+		if value == nil {
+			continue
+		}
+
+		newValue, err := remapReference(resource.Name(), relation, value, report.remappedKeys[relation.ToResource()])
+		if err != nil {
+			return nil, err
+		}
+
+		if !copied {
+			rewritten = make(extract.Row, len(row))
+			for rowColumn, rowValue := range row {
+				rewritten[rowColumn] = rowValue
+			}
+			copied = true
+		}
+		rewritten[column] = newValue
+	}
+
+	return rewritten, nil
+}
+
+func (loader *Loader) referencesRemappedKey(relation configuration.Relation) bool {
+	toResource, exists := loader.configuration.Resource(relation.ToResource())
+	if !exists {
+		return false
+	}
+
+	keyColumn, remapped := loader.remappedKeyColumn(toResource)
+	if !remapped {
+		return false
+	}
+
+	for _, toKey := range relation.ToKeys() {
+		if toKey.Column == keyColumn {
+			return true
+		}
+	}
+
+	return false
+}
+
+func remapReference(resourceName string, relation configuration.Relation, value any, remappedKeys map[string]any) (any, error) {
+	delimitedKey, isDelimited := relation.DelimitedKey()
+	if !isDelimited {
+		newKey, exists := remappedKeys[extract.FormatKeyValue(value)]
+		if !exists {
+			return nil, &UnmappedReferenceError{Resource: resourceName, Relation: relation.QualifiedName(), Value: extract.FormatKeyValue(value)}
+		}
+
+		return newKey, nil
+	}
+
+	keys, err := delimitedKey.Split(extract.FormatKeyValue(value))
+	if err != nil {
+		return nil, fmt.Errorf("remapping relation %q of resource %q: %w", relation.QualifiedName(), resourceName, err)
+	}
+
+	for index, key := range keys {
+		newKey, exists := remappedKeys[key]
+		if !exists {
+			return nil, &UnmappedReferenceError{Resource: resourceName, Relation: relation.QualifiedName(), Value: key}
+		}
+		keys[index] = extract.FormatKeyValue(newKey)
+	}
+
+	return delimitedKey.Join(keys), nil
+}
@@ -0,0 +1,82 @@
+package output
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"entity-works/configuration"
+	"entity-works/extract"
+)
+
+type CSVWriter struct {
+	comma rune
+}
+
+func NewCSVWriter() *CSVWriter {
+	return &CSVWriter{comma: ','}
+}
+
+func (csvWriter CSVWriter) WithComma(comma rune) *CSVWriter {
+	csvWriter.comma = comma
+
+	return &csvWriter
+}
+
+func (csvWriter CSVWriter) FileExtension() string {
+	return ".csv"
+}
+
+func (csvWriter CSVWriter) WriteRows(
+	writer io.Writer,
+	resource configuration.Resource,
+	columns []string,
+	rows []extract.Row,
+) error {
+	recordWriter := csv.NewWriter(writer)
+	recordWriter.Comma = csvWriter.comma
+	if err := recordWriter.Write(columns); err != nil {
+		return err
+	}
+
+	record := make([]string, len(columns))
+	for _, row := range rows {
+		for index, column := range columns {
+			record[index] = formatCSVValue(row[column])
+		}
+		if err := recordWriter.Write(record); err != nil {
+			return err
+		}
+	}
+	recordWriter.Flush()
+
+	return recordWriter.Error()
+}
+
+func formatCSVValue(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case []byte:
+		if utf8.Valid(value) {
+			return string(value)
+		}
+		return base64.StdEncoding.EncodeToString(value)
+	case bool:
+		return strconv.FormatBool(value)
+	case float32:
+		return strconv.FormatFloat(float64(value), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	}
+
+	return fmt.Sprint(value)
+}
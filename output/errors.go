@@ -0,0 +1,22 @@
+package output
+
+import "fmt"
+
+type UnsupportedValueError struct {
+	Resource string
+	Column   string
+	Err      error
+}
+
+func (unsupportedValueError *UnsupportedValueError) Error() string {
+	return fmt.Sprintf(
+		"writing column %q of resource %q: %s",
+		unsupportedValueError.Column,
+		unsupportedValueError.Resource,
+		unsupportedValueError.Err,
+	)
+}
+
+func (unsupportedValueError *UnsupportedValueError) Unwrap() error {
+	return unsupportedValueError.Err
+}
@@ -0,0 +1,120 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"entity-works/configuration"
+	"entity-works/extract"
+)
+
+const bytesValueMarker = "$bytes"
+
+type JSONLinesWriter struct {
+}
+
+func NewJSONLinesWriter() *JSONLinesWriter {
+	return &JSONLinesWriter{}
+}
+
+func (jsonLinesWriter JSONLinesWriter) FileExtension() string {
+	return ".jsonl"
+}
+
+func (jsonLinesWriter JSONLinesWriter) WriteRows(
+	writer io.Writer,
+	resource configuration.Resource,
+	columns []string,
+	rows []extract.Row,
+) error {
+	bufferedWriter := bufio.NewWriter(writer)
+	for _, row := range rows {
+		encodedRow := make(map[string]any, len(columns))
+		for _, column := range columns {
+			encodedRow[column] = EncodeJSONValue(row[column])
+		}
+
+		line, err := json.Marshal(encodedRow)
+		if err != nil {
+			return fmt.Errorf("encoding row of resource %q: %w", resource.Name(), err)
+		}
+		bufferedWriter.Write(line)
+		bufferedWriter.WriteByte('\n')
+	}
+
+	return bufferedWriter.Flush()
+}
+
+func ReadJSONLines(reader io.Reader) ([]extract.Row, error) {
+	var rows []extract.Row
+	bufferedReader := bufio.NewReader(reader)
+	for {
+		line, err := bufferedReader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+
+		if len(bytes.TrimSpace(line)) > 0 {
+			var encodedRow map[string]any
+			decoder := json.NewDecoder(bytes.NewReader(line))
+			decoder.UseNumber()
+			if err := decoder.Decode(&encodedRow); err != nil {
+				return nil, fmt.Errorf("row %d: %w", len(rows)+1, err)
+			}
+
+			row := make(extract.Row, len(encodedRow))
+			for column, value := range encodedRow {
+				row[column] = DecodeJSONValue(value)
+			}
+			rows = append(rows, row)
+		}
+
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+	}
+}
+
+func EncodeJSONValue(value any) any {
+	switch value := value.(type) {
+	case []byte:
+		if utf8.Valid(value) {
+			return string(value)
+		}
+		return map[string]string{bytesValueMarker: base64.StdEncoding.EncodeToString(value)}
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	}
+
+	return value
+}
+
+func DecodeJSONValue(value any) any {
+	switch value := value.(type) {
+	case json.Number:
+		if integer, err := strconv.ParseInt(value.String(), 10, 64); err == nil {
+			return integer
+		}
+		if number, err := value.Float64(); err == nil {
+			return number
+		}
+		return value.String()
+
+	case map[string]any:
+		if encoded, isBytes := value[bytesValueMarker].(string); isBytes && len(value) == 1 {
+			if decoded, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+				return decoded
+			}
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,108 @@
+package output
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"entity-works/configuration"
+	"entity-works/extract"
+	"entity-works/sqlgen"
+)
+
+type SQLWriter struct {
+	dialect        sqlgen.Dialect
+	conflictPolicy sqlgen.ConflictPolicy
+}
+
+func NewSQLWriter(dialect sqlgen.Dialect) *SQLWriter {
+	return &SQLWriter{
+		dialect:        dialect,
+		conflictPolicy: sqlgen.FailOnConflict,
+	}
+}
+
+func (sqlWriter SQLWriter) WithConflictPolicy(conflictPolicy sqlgen.ConflictPolicy) *SQLWriter {
+	sqlWriter.conflictPolicy = conflictPolicy
+
+	return &sqlWriter
+}
+
+func (sqlWriter SQLWriter) FileExtension() string {
+	return ".sql"
+}
+
+func (sqlWriter SQLWriter) WriteRows(
+	writer io.Writer,
+	resource configuration.Resource,
+	columns []string,
+	rows []extract.Row,
+) error {
+	quotedColumns := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quotedColumns = append(quotedColumns, sqlWriter.dialect.QuoteIdentifier(column))
+	}
+
+	keyColumns := make([]string, 0, len(resource.PrimaryKey()))
+	for _, column := range resource.PrimaryKey() {
+		keyColumns = append(keyColumns, sqlWriter.dialect.QuoteIdentifier(column.Column))
+	}
+
+	table := sqlgen.QuoteTable(sqlWriter.dialect, resource.Table())
+	bufferedWriter := bufio.NewWriter(writer)
+	for _, row := range rows {
+		literals := make([]string, 0, len(columns))
+		for _, column := range columns {
+			literal, err := sqlWriter.literal(row[column])
+			if err != nil {
+				return &UnsupportedValueError{Resource: resource.Name(), Column: column, Err: err}
+			}
+			literals = append(literals, literal)
+		}
+
+		values := "(" + strings.Join(literals, ", ") + ")"
+		statement, err := sqlWriter.dialect.Insert(table, quotedColumns, []string{values}, sqlWriter.conflictPolicy, keyColumns)
+		if err != nil {
+			return fmt.Errorf("inserting into resource %q: %w", resource.Name(), err)
+		}
+		bufferedWriter.WriteString(statement)
+		bufferedWriter.WriteString(";\n")
+	}
+
+	return bufferedWriter.Flush()
+}
+
+func (sqlWriter SQLWriter) literal(value any) (string, error) {
+	switch value := value.(type) {
+	case nil:
+		return "NULL", nil
+	case string:
+		return sqlWriter.dialect.QuoteString(value), nil
+	case []byte:
+		return sqlWriter.dialect.BytesLiteral(value), nil
+	case bool:
+		return sqlWriter.dialect.BooleanLiteral(value), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(value), nil
+	case float32:
+		return formatFloat(float64(value), 32)
+	case float64:
+		return formatFloat(value, 64)
+	case time.Time:
+		return sqlWriter.dialect.TimestampLiteral(value), nil
+	}
+
+	return "", fmt.Errorf("unsupported value type %T", value)
+}
+
+func formatFloat(value float64, bitSize int) (string, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return "", fmt.Errorf("non-finite number %v", value)
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, bitSize), nil
+}
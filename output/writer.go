@@ -0,0 +1,84 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"entity-works/configuration"
+	"entity-works/extract"
+)
+
+type RowWriter interface {
+	FileExtension() string
+	WriteRows(writer io.Writer, resource configuration.Resource, columns []string, rows []extract.Row) error
+}
+
+func WriteFiles(
+	directory string,
+	loadedConfiguration *configuration.Configuration,
+	result *extract.Result,
+	rowWriter RowWriter,
+) ([]string, error) {
+	if err := os.MkdirAll(directory, 0o755); err != nil {
+		return nil, err
+	}
+
+	var filePaths []string
+	for _, resourceName := range result.ResourceNames() {
+		resourceRows, _ := result.Resource(resourceName)
+		rows, err := resourceRows.DependencyOrderedRows(loadedConfiguration.Relationships())
+		if err != nil {
+			return nil, fmt.Errorf("ordering rows of resource %q: %w", resourceName, err)
+		}
+
+		filePath := filepath.Join(directory, resourceName+rowWriter.FileExtension())
+		if err := writeFile(filePath, resourceRows, rows, rowWriter); err != nil {
+			return nil, fmt.Errorf("writing resource %q: %w", resourceName, err)
+		}
+		filePaths = append(filePaths, filePath)
+	}
+
+	return filePaths, nil
+}
+
+func WriteScript(
+	writer io.Writer,
+	loadedConfiguration *configuration.Configuration,
+	result *extract.Result,
+	rowWriter RowWriter,
+) error {
+	resourceNames, err := loadedConfiguration.Relationships().DependencyOrder(result.ResourceNames())
+	if err != nil {
+		return fmt.Errorf("ordering resources of entity %q: %w", result.Entity(), err)
+	}
+
+	for _, resourceName := range resourceNames {
+		resourceRows, _ := result.Resource(resourceName)
+		rows, err := resourceRows.DependencyOrderedRows(loadedConfiguration.Relationships())
+		if err != nil {
+			return fmt.Errorf("ordering rows of resource %q: %w", resourceName, err)
+		}
+
+		if err := rowWriter.WriteRows(writer, resourceRows.Resource(), resourceRows.Columns(), rows); err != nil {
+			return fmt.Errorf("writing resource %q: %w", resourceName, err)
+		}
+	}
+
+	return nil
+}
+
+func writeFile(filePath string, resourceRows *extract.ResourceRows, rows []extract.Row, rowWriter RowWriter) (err error) {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	return rowWriter.WriteRows(file, resourceRows.Resource(), resourceRows.Columns(), rows)
+}
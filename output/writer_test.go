@@ -0,0 +1,200 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"entity-works/configuration"
+	"entity-works/extract"
+	"entity-works/internal/testdb"
+	"entity-works/sqlgen"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const shopSchema = `
+CREATE TABLE customers (id INTEGER PRIMARY KEY, name TEXT, avatar BLOB, score REAL);
+CREATE TABLE orders (id INTEGER PRIMARY KEY, customer_id INTEGER NOT NULL REFERENCES customers (id), total INTEGER);
+CREATE TABLE categories (id INTEGER PRIMARY KEY, parent_id INTEGER REFERENCES categories (id), name TEXT);
+`
+
+const shopYmlConfiguration = `Name: Shop
+Resources:
+  Customers:
+    TableName: customers
+    PrimaryKey:
+      - customers.id
+  Orders:
+    TableName: orders
+    PrimaryKey:
+      - orders.id
+    ForeignKeys:
+      - Type: NORMAL
+        Key: orders.customer_id
+        ResourceName: Customers
+        ForeignKey: customers.id
+  Categories:
+    TableName: categories
+    PrimaryKey:
+      - categories.id
+    ForeignKeys:
+      - Type: NORMAL
+        Name: Parent
+        Key: categories.parent_id
+        ResourceName: Categories
+        ForeignKey: categories.id
+Entities: {}
+`
+
+func shopResult(t *testing.T) (*configuration.Configuration, *extract.Result) {
+	loadedConfiguration, err := configuration.LoadReader(strings.NewReader(shopYmlConfiguration))
+	assert.Nil(t, err)
+
+	result := extract.NewResult("CoreProduct", nil)
+	addRows := func(resourceName string, columns []string, rows ...extract.Row) {
+		resource, exists := loadedConfiguration.Resource(resourceName)
+		assert.True(t, exists, resourceName)
+		result.AddRows(resource, columns, rows)
+	}
+	addRows(
+		"Categories",
+		[]string{"id", "parent_id", "name"},
+		extract.Row{"id": int64(3), "parent_id": int64(2), "name": "Fiction"},
+		extract.Row{"id": int64(2), "parent_id": int64(1), "name": "Books"},
+		extract.Row{"id": int64(1), "parent_id": nil, "name": "All"},
+	)
+	addRows(
+		"Customers",
+		[]string{"id", "name", "avatar", "score"},
+		extract.Row{"id": int64(1), "name": `Ada, "the first"`, "avatar": []byte{0xff, 0x00}, "score": 1.5},
+		extract.Row{"id": int64(2), "name": "Grace", "avatar": nil, "score": int64(2)},
+	)
+	addRows(
+		"Orders",
+		[]string{"id", "customer_id", "total"},
+		extract.Row{"id": int64(10), "customer_id": int64(1), "total": int64(50)},
+		extract.Row{"id": int64(11), "customer_id": int64(2), "total": int64(150)},
+	)
+
+	return loadedConfiguration, result
+}
+
+func TestJSONLinesWriterRoundTripsRows(t *testing.T) {
+	loadedConfiguration, result := shopResult(t)
+	directory := t.TempDir()
+
+	filePaths, err := WriteFiles(directory, loadedConfiguration, result, NewJSONLinesWriter())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(directory, "Categories.jsonl"),
+		filepath.Join(directory, "Customers.jsonl"),
+		filepath.Join(directory, "Orders.jsonl"),
+	}, filePaths)
+
+	file, err := os.Open(filepath.Join(directory, "Customers.jsonl"))
+	assert.Nil(t, err)
+	defer file.Close()
+
+	rows, err := ReadJSONLines(file)
+	assert.Nil(t, err)
+	assert.Equal(t, []extract.Row{
+		{"id": int64(1), "name": `Ada, "the first"`, "avatar": []byte{0xff, 0x00}, "score": 1.5},
+		{"id": int64(2), "name": "Grace", "avatar": nil, "score": int64(2)},
+	}, rows)
+
+	contents, err := os.ReadFile(filepath.Join(directory, "Categories.jsonl"))
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		`{"id":1,"name":"All","parent_id":null}`+"\n"+
+			`{"id":2,"name":"Books","parent_id":1}`+"\n"+
+			`{"id":3,"name":"Fiction","parent_id":2}`+"\n",
+		string(contents),
+	)
+}
+
+func TestCSVWriterWritesAHeaderFromColumnNames(t *testing.T) {
+	loadedConfiguration, result := shopResult(t)
+	directory := t.TempDir()
+
+	_, err := WriteFiles(directory, loadedConfiguration, result, NewCSVWriter())
+	assert.Nil(t, err)
+
+	contents, err := os.ReadFile(filepath.Join(directory, "Customers.csv"))
+	assert.Nil(t, err)
+	assert.Equal(t, "id,name,avatar,score\n1,\"Ada, \"\"the first\"\"\",/wA=,1.5\n2,Grace,,2\n", string(contents))
+
+	resourceRows, _ := result.Resource("Orders")
+	var semicolonSeparated bytes.Buffer
+	err = NewCSVWriter().WithComma(';').WriteRows(&semicolonSeparated, resourceRows.Resource(), resourceRows.Columns(), resourceRows.Rows())
+	assert.Nil(t, err)
+	assert.Equal(t, "id;customer_id;total\n10;1;50\n11;2;150\n", semicolonSeparated.String())
+}
+
+func TestSQLWriterWritesAScriptInDependencyOrder(t *testing.T) {
+	loadedConfiguration, result := shopResult(t)
+
+	var script bytes.Buffer
+	assert.Nil(t, WriteScript(&script, loadedConfiguration, result, NewSQLWriter(sqlgen.NewSQLiteDialect())))
+	assert.Equal(t, strings.Join([]string{
+		`INSERT INTO "categories" ("id", "parent_id", "name") VALUES (1, NULL, 'All');`,
+		`INSERT INTO "categories" ("id", "parent_id", "name") VALUES (2, 1, 'Books');`,
+		`INSERT INTO "categories" ("id", "parent_id", "name") VALUES (3, 2, 'Fiction');`,
+		`INSERT INTO "customers" ("id", "name", "avatar", "score") VALUES (1, 'Ada, "the first"', X'ff00', 1.5);`,
+		`INSERT INTO "customers" ("id", "name", "avatar", "score") VALUES (2, 'Grace', NULL, 2);`,
+		`INSERT INTO "orders" ("id", "customer_id", "total") VALUES (10, 1, 50);`,
+		`INSERT INTO "orders" ("id", "customer_id", "total") VALUES (11, 2, 150);`,
+	}, "\n")+"\n", script.String())
+
+	target := testdb.Open(t, shopSchema+script.String())
+	var loaded int
+	assert.Nil(t, target.QueryRow("SELECT COUNT(*) FROM orders JOIN customers ON customers.id = orders.customer_id").Scan(&loaded))
+	assert.Equal(t, 2, loaded)
+
+	script.Reset()
+	postgresWriter := NewSQLWriter(sqlgen.NewPostgresDialect()).WithConflictPolicy(sqlgen.SkipOnConflict)
+	resourceRows, _ := result.Resource("Customers")
+	assert.Nil(t, postgresWriter.WriteRows(&script, resourceRows.Resource(), resourceRows.Columns(), resourceRows.Rows()[:1]))
+	assert.Equal(
+		t,
+		`INSERT INTO "customers" ("id", "name", "avatar", "score") VALUES (1, 'Ada, "the first"', '\xff00', 1.5) ON CONFLICT ("id") DO NOTHING;`+"\n",
+		script.String(),
+	)
+
+	err := postgresWriter.WriteRows(&script, resourceRows.Resource(), []string{"id"}, []extract.Row{{"id": struct{}{}}})
+	var unsupportedValueError *UnsupportedValueError
+	assert.True(t, errors.As(err, &unsupportedValueError))
+	assert.Equal(t, "Customers", unsupportedValueError.Resource)
+}
+
+func TestSQLWriterFormatsTimestampsForEachDialect(t *testing.T) {
+	loadedConfiguration, _ := shopResult(t)
+	orders, _ := loadedConfiguration.Resource("Orders")
+	rows := []extract.Row{{"id": int64(10), "placed_at": time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}}
+
+	for _, testCase := range []struct {
+		dialect  sqlgen.Dialect
+		expected string
+	}{
+		{sqlgen.NewMySQLDialect(), "INSERT INTO `orders` (`id`, `placed_at`) VALUES (10, '2024-01-02 03:04:05');\n"},
+		{sqlgen.NewPostgresDialect(), `INSERT INTO "orders" ("id", "placed_at") VALUES (10, '2024-01-02 03:04:05+00:00');` + "\n"},
+		{sqlgen.NewSQLiteDialect(), `INSERT INTO "orders" ("id", "placed_at") VALUES (10, '2024-01-02 03:04:05');` + "\n"},
+	} {
+		var script bytes.Buffer
+		assert.Nil(t, NewSQLWriter(testCase.dialect).WriteRows(&script, orders, []string{"id", "placed_at"}, rows))
+		assert.Equal(t, testCase.expected, script.String(), testCase.dialect.Name())
+	}
+}
+
+func TestReadJSONLinesReadsRowsLongerThanAScannerBuffer(t *testing.T) {
+	long := strings.Repeat("x", 1<<20)
+
+	rows, err := ReadJSONLines(strings.NewReader(`{"id":1}` + "\n\n" + `{"id":2,"notes":"` + long + `"}`))
+	assert.Nil(t, err)
+	assert.Equal(t, []extract.Row{{"id": int64(1)}, {"id": int64(2), "notes": long}}, rows)
+}
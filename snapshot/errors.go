@@ -0,0 +1,54 @@
+package snapshot
+
+import "fmt"
+
+type UnsupportedVersionError struct {
+	Version int
+}
+
+func (unsupportedVersionError *UnsupportedVersionError) Error() string {
+	return fmt.Sprintf("unsupported snapshot version %d, expected %d", unsupportedVersionError.Version, manifestVersion)
+}
+
+type ChecksumMismatchError struct {
+	File     string
+	Expected string
+	Actual   string
+}
+
+func (checksumMismatchError *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf(
+		"file %q has checksum %s, manifest expects %s",
+		checksumMismatchError.File,
+		checksumMismatchError.Actual,
+		checksumMismatchError.Expected,
+	)
+}
+
+type RowCountMismatchError struct {
+	File     string
+	Expected int
+	Actual   int
+}
+
+func (rowCountMismatchError *RowCountMismatchError) Error() string {
+	return fmt.Sprintf(
+		"file %q has %d rows, manifest expects %d",
+		rowCountMismatchError.File,
+		rowCountMismatchError.Actual,
+		rowCountMismatchError.Expected,
+	)
+}
+
+type ConfigurationMismatchError struct {
+	Expected string
+	Actual   string
+}
+
+func (configurationMismatchError *ConfigurationMismatchError) Error() string {
+	return fmt.Sprintf(
+		"snapshot was taken with configuration %q, not %q",
+		configurationMismatchError.Expected,
+		configurationMismatchError.Actual,
+	)
+}
@@ -0,0 +1,272 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"entity-works/configuration"
+	"entity-works/extract"
+	"entity-works/output"
+)
+
+const (
+	manifestVersion = 1
+	manifestFile    = "manifest.json"
+	resourcesDir    = "resources"
+)
+
+type Manifest struct {
+	Version       int
+	Configuration string
+	Entity        string
+	Parameters    map[string]any
+	Resources     []ResourceManifest
+}
+
+type ResourceManifest struct {
+	Resource string
+	File     string
+	Columns  []string
+	Rows     int
+	SHA256   string
+}
+
+type Snapshot struct {
+	manifest  Manifest
+	resources map[string][]extract.Row
+}
+
+func (snapshot Snapshot) Manifest() Manifest {
+	return snapshot.manifest
+}
+
+func (snapshot Snapshot) Result(loadedConfiguration *configuration.Configuration) (*extract.Result, error) {
+	if loadedConfiguration.Name() != snapshot.manifest.Configuration {
+		return nil, &ConfigurationMismatchError{Expected: snapshot.manifest.Configuration, Actual: loadedConfiguration.Name()}
+	}
+
+	result := extract.NewResult(snapshot.manifest.Entity, snapshot.manifest.Parameters)
+	for _, resourceManifest := range snapshot.manifest.Resources {
+		resource, exists := loadedConfiguration.Resource(resourceManifest.Resource)
+		if !exists {
+			return nil, &configuration.UnknownResourceError{Resource: resourceManifest.Resource}
+		}
+
+		result.AddRows(resource, resourceManifest.Columns, snapshot.resources[resourceManifest.Resource])
+	}
+
+	return result, nil
+}
+
+func WriteDirectory(directory string, loadedConfiguration *configuration.Configuration, result *extract.Result) (*Manifest, error) {
+	manifest, files, err := encode(loadedConfiguration, result)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range append(fileNames(manifest), manifestFile) {
+		filePath := filepath.Join(directory, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(filePath, files[name], 0o644); err != nil {
+			return nil, err
+		}
+	}
+
+	return manifest, nil
+}
+
+func WriteArchive(writer io.Writer, loadedConfiguration *configuration.Configuration, result *extract.Result) (*Manifest, error) {
+	manifest, files, err := encode(loadedConfiguration, result)
+	if err != nil {
+		return nil, err
+	}
+
+	gzipWriter := gzip.NewWriter(writer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, name := range append([]string{manifestFile}, fileNames(manifest)...) {
+		header := &tar.Header{
+			Name:    name,
+			Mode:    0o644,
+			Size:    int64(len(files[name])),
+			ModTime: time.Unix(0, 0),
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return nil, err
+		}
+		if _, err := tarWriter.Write(files[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+func ReadDirectory(directory string) (*Snapshot, error) {
+	return Read(os.DirFS(directory))
+}
+
+func Read(fsys fs.FS) (*Snapshot, error) {
+	return decode(func(name string) ([]byte, error) {
+		return fs.ReadFile(fsys, name)
+	})
+}
+
+func ReadArchive(reader io.Reader) (*Snapshot, error) {
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshot archive: %w", err)
+	}
+	defer gzipReader.Close()
+
+	files := make(map[string][]byte)
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading snapshot archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		contents, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, fmt.Errorf("reading snapshot archive entry %q: %w", header.Name, err)
+		}
+		files[path.Clean(header.Name)] = contents
+	}
+
+	return decode(func(name string) ([]byte, error) {
+		contents, exists := files[name]
+		if !exists {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+		}
+
+		return contents, nil
+	})
+}
+
+func encode(loadedConfiguration *configuration.Configuration, result *extract.Result) (*Manifest, map[string][]byte, error) {
+	manifest := &Manifest{
+		Version:       manifestVersion,
+		Configuration: loadedConfiguration.Name(),
+		Entity:        result.Entity(),
+		Parameters:    result.Parameters(),
+	}
+
+	files := make(map[string][]byte)
+	rowWriter := output.NewJSONLinesWriter()
+	for _, resourceName := range result.ResourceNames() {
+		resourceRows, _ := result.Resource(resourceName)
+		columns := resourceRows.Columns()
+
+		var contents bytes.Buffer
+		if err := rowWriter.WriteRows(&contents, resourceRows.Resource(), columns, resourceRows.Rows()); err != nil {
+			return nil, nil, err
+		}
+
+		name := path.Join(resourcesDir, resourceName+rowWriter.FileExtension())
+		files[name] = contents.Bytes()
+		manifest.Resources = append(manifest.Resources, ResourceManifest{
+			Resource: resourceName,
+			File:     name,
+			Columns:  columns,
+			Rows:     resourceRows.Len(),
+			SHA256:   checksum(contents.Bytes()),
+		})
+	}
+
+	encodedManifest, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("encoding manifest: %w", err)
+	}
+	files[manifestFile] = append(encodedManifest, '\n')
+
+	return manifest, files, nil
+}
+
+func decode(readFile func(name string) ([]byte, error)) (*Snapshot, error) {
+	encodedManifest, err := readFile(manifestFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+
+	var manifest Manifest
+	decoder := json.NewDecoder(bytes.NewReader(encodedManifest))
+	decoder.UseNumber()
+	if err := decoder.Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("decoding manifest: %w", err)
+	}
+	if manifest.Version != manifestVersion {
+		return nil, &UnsupportedVersionError{Version: manifest.Version}
+	}
+	for name, value := range manifest.Parameters {
+		manifest.Parameters[name] = output.DecodeJSONValue(value)
+	}
+
+	snapshot := &Snapshot{
+		manifest:  manifest,
+		resources: make(map[string][]extract.Row),
+	}
+	for _, resourceManifest := range manifest.Resources {
+		contents, err := readFile(resourceManifest.File)
+		if err != nil {
+			return nil, fmt.Errorf("reading resource %q: %w", resourceManifest.Resource, err)
+		}
+
+		if actual := checksum(contents); actual != resourceManifest.SHA256 {
+			return nil, &ChecksumMismatchError{File: resourceManifest.File, Expected: resourceManifest.SHA256, Actual: actual}
+		}
+
+		rows, err := output.ReadJSONLines(bytes.NewReader(contents))
+		if err != nil {
+			return nil, fmt.Errorf("decoding resource %q: %w", resourceManifest.Resource, err)
+		}
+		if len(rows) != resourceManifest.Rows {
+			return nil, &RowCountMismatchError{File: resourceManifest.File, Expected: resourceManifest.Rows, Actual: len(rows)}
+		}
+
+		snapshot.resources[resourceManifest.Resource] = rows
+	}
+
+	return snapshot, nil
+}
+
+func fileNames(manifest *Manifest) []string {
+	names := make([]string, 0, len(manifest.Resources))
+	for _, resourceManifest := range manifest.Resources {
+		names = append(names, resourceManifest.File)
+	}
+
+	return names
+}
+
+func checksum(contents []byte) string {
+	sum := sha256.Sum256(contents)
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,154 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"entity-works/configuration"
+	"entity-works/extract"
+	"entity-works/internal/testdb"
+	"entity-works/load"
+	"entity-works/sqlgen"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const shopSchema = `
+CREATE TABLE customers (id INTEGER PRIMARY KEY, name TEXT, avatar BLOB, score REAL);
+CREATE TABLE orders (id INTEGER PRIMARY KEY, customer_id INTEGER NOT NULL REFERENCES customers (id), total INTEGER);
+`
+
+const shopYmlConfiguration = `Name: Shop
+Resources:
+  Customers:
+    TableName: customers
+    PrimaryKey:
+      - customers.id
+  Orders:
+    TableName: orders
+    PrimaryKey:
+      - orders.id
+    ForeignKeys:
+      - Type: NORMAL
+        Key: orders.customer_id
+        ResourceName: Customers
+        ForeignKey: customers.id
+Entities: {}
+`
+
+func shopResult(t *testing.T) (*configuration.Configuration, *extract.Result) {
+	loadedConfiguration, err := configuration.LoadReader(strings.NewReader(shopYmlConfiguration))
+	assert.Nil(t, err)
+
+	customers, _ := loadedConfiguration.Resource("Customers")
+	orders, _ := loadedConfiguration.Resource("Orders")
+	result := extract.NewResult("CoreProduct", map[string]any{"minimum": int64(50)})
+	result.AddRows(customers, []string{"id", "name", "avatar", "score"}, []extract.Row{
+		{"id": int64(1), "name": "Ada", "avatar": []byte{0xff, 0x00}, "score": 1.5},
+		{"id": int64(2), "name": "Grace", "avatar": nil, "score": int64(2)},
+	})
+	result.AddRows(orders, []string{"id", "customer_id", "total"}, []extract.Row{
+		{"id": int64(10), "customer_id": int64(1), "total": int64(50)},
+		{"id": int64(11), "customer_id": int64(2), "total": int64(150)},
+	})
+
+	return loadedConfiguration, result
+}
+
+func assertLoadsIntoEmptyDatabase(t *testing.T, loadedConfiguration *configuration.Configuration, snapshot *Snapshot) {
+	result, err := snapshot.Result(loadedConfiguration)
+	assert.Nil(t, err)
+
+	target := testdb.Open(t, shopSchema)
+	_, err = load.NewLoader(loadedConfiguration, target, sqlgen.NewSQLiteDialect()).Load(context.Background(), result)
+	assert.Nil(t, err)
+
+	rows, err := target.Query("SELECT customers.name, hex(customers.avatar), orders.total FROM orders JOIN customers ON customers.id = orders.customer_id ORDER BY orders.id")
+	assert.Nil(t, err)
+	defer rows.Close()
+
+	var loaded []string
+	for rows.Next() {
+		var name, avatar, total string
+		assert.Nil(t, rows.Scan(&name, &avatar, &total))
+		loaded = append(loaded, name+":"+avatar+":"+total)
+	}
+	assert.Equal(t, []string{"Ada:FF00:50", "Grace::150"}, loaded)
+}
+
+func TestSnapshotRoundTripsThroughADirectory(t *testing.T) {
+	loadedConfiguration, result := shopResult(t)
+	directory := t.TempDir()
+
+	manifest, err := WriteDirectory(directory, loadedConfiguration, result)
+	assert.Nil(t, err)
+	assert.Equal(t, "Shop", manifest.Configuration)
+	assert.Equal(t, "CoreProduct", manifest.Entity)
+	assert.Equal(t, map[string]any{"minimum": int64(50)}, manifest.Parameters)
+	assert.Len(t, manifest.Resources, 2)
+	assert.Equal(t, ResourceManifest{
+		Resource: "Customers",
+		File:     "resources/Customers.jsonl",
+		Columns:  []string{"id", "name", "avatar", "score"},
+		Rows:     2,
+		SHA256:   manifest.Resources[0].SHA256,
+	}, manifest.Resources[0])
+
+	contents, err := os.ReadFile(filepath.Join(directory, "resources", "Customers.jsonl"))
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		`{"avatar":{"$bytes":"/wA="},"id":1,"name":"Ada","score":1.5}`+"\n"+
+			`{"avatar":null,"id":2,"name":"Grace","score":2}`+"\n",
+		string(contents),
+	)
+
+	snapshot, err := ReadDirectory(directory)
+	assert.Nil(t, err)
+	assert.Equal(t, *manifest, snapshot.Manifest())
+	assertLoadsIntoEmptyDatabase(t, loadedConfiguration, snapshot)
+}
+
+func TestSnapshotRoundTripsThroughAnArchive(t *testing.T) {
+	loadedConfiguration, result := shopResult(t)
+
+	var archive bytes.Buffer
+	_, err := WriteArchive(&archive, loadedConfiguration, result)
+	assert.Nil(t, err)
+
+	var again bytes.Buffer
+	_, err = WriteArchive(&again, loadedConfiguration, result)
+	assert.Nil(t, err)
+	assert.Equal(t, archive.Bytes(), again.Bytes())
+
+	snapshot, err := ReadArchive(&archive)
+	assert.Nil(t, err)
+	assertLoadsIntoEmptyDatabase(t, loadedConfiguration, snapshot)
+}
+
+func TestSnapshotRejectsTamperedFilesAndOtherConfigurations(t *testing.T) {
+	loadedConfiguration, result := shopResult(t)
+	directory := t.TempDir()
+	_, err := WriteDirectory(directory, loadedConfiguration, result)
+	assert.Nil(t, err)
+
+	snapshot, err := ReadDirectory(directory)
+	assert.Nil(t, err)
+	otherConfiguration, err := configuration.LoadReader(strings.NewReader(strings.Replace(shopYmlConfiguration, "Name: Shop", "Name: Warehouse", 1)))
+	assert.Nil(t, err)
+	_, err = snapshot.Result(otherConfiguration)
+	var configurationMismatchError *ConfigurationMismatchError
+	assert.True(t, errors.As(err, &configurationMismatchError))
+
+	ordersFile := filepath.Join(directory, "resources", "Orders.jsonl")
+	assert.Nil(t, os.WriteFile(ordersFile, []byte(`{"customer_id":1,"id":10,"total":5000}`+"\n"), 0o644))
+	_, err = ReadDirectory(directory)
+	var checksumMismatchError *ChecksumMismatchError
+	assert.True(t, errors.As(err, &checksumMismatchError))
+	assert.Equal(t, "resources/Orders.jsonl", checksumMismatchError.File)
+}
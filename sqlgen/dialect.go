@@ -0,0 +1,107 @@
+package sqlgen
+
+import (
+	"strings"
+	"time"
+
+	"entity-works/configuration"
+)
+
+type Dialect interface {
+	Name() string
+	QuoteIdentifier(identifier string) string
+	QuoteString(value string) string
+	Placeholder(position int) string
+	Limit(limit int) string
+	BooleanLiteral(value bool) string
+	BytesLiteral(value []byte) string
+	TimestampLiteral(value time.Time) string
+	DelimitedContains(list string, value string, delimitedKey configuration.DelimitedKey) (string, error)
+	Insert(table string, columns []string, rows []string, conflictPolicy ConflictPolicy, keyColumns []string) (string, error)
+	InsertDefaultValues(table string) string
+	Returning(column string) string
+}
+
+type ConflictPolicy string
+
+const (
+	FailOnConflict   ConflictPolicy = "fail"
+	SkipOnConflict   ConflictPolicy = "skip"
+	UpsertOnConflict ConflictPolicy = "upsert"
+)
+
+func insertInto(table string, columns []string, rows []string) string {
+	return "INTO " + table + " (" + strings.Join(columns, ", ") + ") VALUES " + strings.Join(rows, ", ")
+}
+
+func updatedColumns(columns []string, keyColumns []string) []string {
+	keys := make(map[string]bool, len(keyColumns))
+	for _, keyColumn := range keyColumns {
+		keys[keyColumn] = true
+	}
+
+	var updated []string
+	for _, column := range columns {
+		if !keys[column] {
+			updated = append(updated, column)
+		}
+	}
+
+	return updated
+}
+
+func onConflict(table string, columns []string, rows []string, conflictPolicy ConflictPolicy, keyColumns []string) (string, error) {
+	statement := "INSERT " + insertInto(table, columns, rows)
+	switch conflictPolicy {
+	case FailOnConflict:
+		return statement, nil
+
+	case SkipOnConflict:
+		if len(keyColumns) == 0 {
+			return statement + " ON CONFLICT DO NOTHING", nil
+		}
+		return statement + " ON CONFLICT (" + strings.Join(keyColumns, ", ") + ") DO NOTHING", nil
+
+	case UpsertOnConflict:
+		if len(keyColumns) == 0 {
+			return "", &UpsertWithoutKeyError{Table: table}
+		}
+
+		statement += " ON CONFLICT (" + strings.Join(keyColumns, ", ") + ")"
+		updated := updatedColumns(columns, keyColumns)
+		if len(updated) == 0 {
+			return statement + " DO NOTHING", nil
+		}
+
+		assignments := make([]string, 0, len(updated))
+		for _, column := range updated {
+			assignments = append(assignments, column+" = excluded."+column)
+		}
+		return statement + " DO UPDATE SET " + strings.Join(assignments, ", "), nil
+	}
+
+	return "", &UnknownConflictPolicyError{ConflictPolicy: conflictPolicy}
+}
+
+func quoteWith(quote string, identifier string) string {
+	return quote + strings.ReplaceAll(identifier, quote, quote+quote) + quote
+}
+
+func QuoteTable(dialect Dialect, table configuration.TableRef) string {
+	return QuoteIdentifiers(dialect, table.Schema, table.Table)
+}
+
+func QuoteColumn(dialect Dialect, column configuration.ColumnRef) string {
+	return QuoteIdentifiers(dialect, column.Schema, column.Table, column.Column)
+}
+
+func QuoteIdentifiers(dialect Dialect, identifiers ...string) string {
+	var quotedIdentifiers []string
+	for _, identifier := range identifiers {
+		if identifier != "" {
+			quotedIdentifiers = append(quotedIdentifiers, dialect.QuoteIdentifier(identifier))
+		}
+	}
+
+	return strings.Join(quotedIdentifiers, ".")
+}
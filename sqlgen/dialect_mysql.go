@@ -0,0 +1,127 @@
+package sqlgen
+
+import (
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"entity-works/configuration"
+)
+
+type MySQLDialect struct {
+}
+
+func NewMySQLDialect() *MySQLDialect {
+	return &MySQLDialect{}
+}
+
+func (mySQLDialect MySQLDialect) Name() string {
+	return "mysql"
+}
+
+func (mySQLDialect MySQLDialect) QuoteIdentifier(identifier string) string {
+	return quoteWith("`", identifier)
+}
+
+func (mySQLDialect MySQLDialect) QuoteString(value string) string {
+	return quoteWith("'", strings.ReplaceAll(value, `\`, `\\`))
+}
+
+func (mySQLDialect MySQLDialect) Placeholder(position int) string {
+	return "?"
+}
+
+func (mySQLDialect MySQLDialect) Limit(limit int) string {
+	return fmt.Sprintf(" LIMIT %d", limit)
+}
+
+func (mySQLDialect MySQLDialect) BooleanLiteral(value bool) string {
+	if value {
+		return "TRUE"
+	}
+
+	return "FALSE"
+}
+
+func (mySQLDialect MySQLDialect) BytesLiteral(value []byte) string {
+	return "X'" + hex.EncodeToString(value) + "'"
+}
+
+func (mySQLDialect MySQLDialect) TimestampLiteral(value time.Time) string {
+	return mySQLDialect.QuoteString(value.Format("2006-01-02 15:04:05.999999"))
+}
+
+func (mySQLDialect MySQLDialect) DelimitedContains(
+	list string,
+	value string,
+	delimitedKey configuration.DelimitedKey,
+) (string, error) {
+	switch delimitedKey.Format {
+	case configuration.TextDelimitedFormat:
+		if delimitedKey.Trim {
+			list = fmt.Sprintf(
+				"REGEXP_REPLACE(REGEXP_REPLACE(%s, %s, ','), '^ +| +$', '')",
+				list,
+				mySQLDialect.QuoteString(" *"+regexp.QuoteMeta(delimitedKey.EffectiveDelimiter())+" *"),
+			)
+		} else if delimitedKey.EffectiveDelimiter() != "," {
+			list = fmt.Sprintf("REPLACE(%s, %s, ',')", list, mySQLDialect.QuoteString(delimitedKey.EffectiveDelimiter()))
+		}
+		return fmt.Sprintf("FIND_IN_SET(CAST(%s AS CHAR), %s) > 0", value, list), nil
+
+	case configuration.JSONDelimitedFormat:
+		return fmt.Sprintf(
+			"(JSON_CONTAINS(%s, JSON_ARRAY(%s)) OR JSON_CONTAINS(%s, JSON_ARRAY(CAST(%s AS CHAR))))",
+			list,
+			value,
+			list,
+			value,
+		), nil
+	}
+
+	return "", &UnsupportedDelimitedFormatError{Dialect: mySQLDialect.Name(), Format: delimitedKey.Format}
+}
+
+func (mySQLDialect MySQLDialect) Insert(
+	table string,
+	columns []string,
+	rows []string,
+	conflictPolicy ConflictPolicy,
+	keyColumns []string,
+) (string, error) {
+	switch conflictPolicy {
+	case FailOnConflict:
+		return "INSERT " + insertInto(table, columns, rows), nil
+
+	case SkipOnConflict:
+		return "INSERT IGNORE " + insertInto(table, columns, rows), nil
+
+	case UpsertOnConflict:
+		if len(keyColumns) == 0 {
+			return "", &UpsertWithoutKeyError{Table: table}
+		}
+
+		updated := updatedColumns(columns, keyColumns)
+		if len(updated) == 0 {
+			updated = keyColumns[:1]
+		}
+
+		assignments := make([]string, 0, len(updated))
+		for _, column := range updated {
+			assignments = append(assignments, column+" = VALUES("+column+")")
+		}
+		return "INSERT " + insertInto(table, columns, rows) + " ON DUPLICATE KEY UPDATE " + strings.Join(assignments, ", "), nil
+	}
+
+	return "", &UnknownConflictPolicyError{ConflictPolicy: conflictPolicy}
+}
+
+func (mySQLDialect MySQLDialect) InsertDefaultValues(table string) string {
+	return "INSERT INTO " + table + " () VALUES ()"
+}
+
+func (mySQLDialect MySQLDialect) Returning(column string) string {
+	return ""
+}
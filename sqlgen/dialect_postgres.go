@@ -0,0 +1,93 @@
+package sqlgen
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"entity-works/configuration"
+)
+
+type PostgresDialect struct {
+}
+
+func NewPostgresDialect() *PostgresDialect {
+	return &PostgresDialect{}
+}
+
+func (postgresDialect PostgresDialect) Name() string {
+	return "postgres"
+}
+
+func (postgresDialect PostgresDialect) QuoteIdentifier(identifier string) string {
+	return quoteWith(`"`, identifier)
+}
+
+func (postgresDialect PostgresDialect) QuoteString(value string) string {
+	return quoteWith("'", value)
+}
+
+func (postgresDialect PostgresDialect) Placeholder(position int) string {
+	return fmt.Sprintf("$%d", position)
+}
+
+func (postgresDialect PostgresDialect) Limit(limit int) string {
+	return fmt.Sprintf(" LIMIT %d", limit)
+}
+
+func (postgresDialect PostgresDialect) BooleanLiteral(value bool) string {
+	if value {
+		return "TRUE"
+	}
+
+	return "FALSE"
+}
+
+func (postgresDialect PostgresDialect) BytesLiteral(value []byte) string {
+	return "'\\x" + hex.EncodeToString(value) + "'"
+}
+
+func (postgresDialect PostgresDialect) TimestampLiteral(value time.Time) string {
+	return postgresDialect.QuoteString(value.Format("2006-01-02 15:04:05.999999-07:00"))
+}
+
+func (postgresDialect PostgresDialect) DelimitedContains(
+	list string,
+	value string,
+	delimitedKey configuration.DelimitedKey,
+) (string, error) {
+	switch delimitedKey.Format {
+	case configuration.TextDelimitedFormat:
+		items := fmt.Sprintf("string_to_array(%s, %s)", list, postgresDialect.QuoteString(delimitedKey.EffectiveDelimiter()))
+		if delimitedKey.Trim {
+			return fmt.Sprintf("CAST(%s AS TEXT) IN (SELECT btrim(item) FROM unnest(%s) AS item)", value, items), nil
+		}
+		return fmt.Sprintf("CAST(%s AS TEXT) = ANY(%s)", value, items), nil
+
+	case configuration.JSONDelimitedFormat:
+		return fmt.Sprintf("CAST(%s AS TEXT) IN (SELECT jsonb_array_elements_text(CAST(%s AS JSONB)))", value, list), nil
+
+	case configuration.ArrayDelimitedFormat:
+		return fmt.Sprintf("CAST(%s AS TEXT) = ANY(CAST(%s AS TEXT[]))", value, list), nil
+	}
+
+	return "", &UnsupportedDelimitedFormatError{Dialect: postgresDialect.Name(), Format: delimitedKey.Format}
+}
+
+func (postgresDialect PostgresDialect) Insert(
+	table string,
+	columns []string,
+	rows []string,
+	conflictPolicy ConflictPolicy,
+	keyColumns []string,
+) (string, error) {
+	return onConflict(table, columns, rows, conflictPolicy, keyColumns)
+}
+
+func (postgresDialect PostgresDialect) InsertDefaultValues(table string) string {
+	return "INSERT INTO " + table + " DEFAULT VALUES"
+}
+
+func (postgresDialect PostgresDialect) Returning(column string) string {
+	return " RETURNING " + column
+}
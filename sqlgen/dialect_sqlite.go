@@ -0,0 +1,113 @@
+package sqlgen
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"entity-works/configuration"
+)
+
+type SQLiteDialect struct {
+}
+
+func NewSQLiteDialect() *SQLiteDialect {
+	return &SQLiteDialect{}
+}
+
+func (sqliteDialect SQLiteDialect) Name() string {
+	return "sqlite"
+}
+
+func (sqliteDialect SQLiteDialect) QuoteIdentifier(identifier string) string {
+	return quoteWith(`"`, identifier)
+}
+
+func (sqliteDialect SQLiteDialect) QuoteString(value string) string {
+	return quoteWith("'", value)
+}
+
+func (sqliteDialect SQLiteDialect) Placeholder(position int) string {
+	return "?"
+}
+
+func (sqliteDialect SQLiteDialect) Limit(limit int) string {
+	return fmt.Sprintf(" LIMIT %d", limit)
+}
+
+func (sqliteDialect SQLiteDialect) BooleanLiteral(value bool) string {
+	if value {
+		return "1"
+	}
+
+	return "0"
+}
+
+func (sqliteDialect SQLiteDialect) BytesLiteral(value []byte) string {
+	return "X'" + hex.EncodeToString(value) + "'"
+}
+
+func (sqliteDialect SQLiteDialect) TimestampLiteral(value time.Time) string {
+	return sqliteDialect.QuoteString(value.Format("2006-01-02 15:04:05.999999"))
+}
+
+func (sqliteDialect SQLiteDialect) DelimitedContains(
+	list string,
+	value string,
+	delimitedKey configuration.DelimitedKey,
+) (string, error) {
+	switch delimitedKey.Format {
+	case configuration.TextDelimitedFormat:
+		delimiter := sqliteDialect.QuoteString(delimitedKey.EffectiveDelimiter())
+		if delimitedKey.Trim {
+			return fmt.Sprintf(
+				`EXISTS (WITH RECURSIVE "delimited_items"("item", "rest") AS (SELECT NULL, %s || %s `+
+					`UNION ALL SELECT substr("rest", 1, instr("rest", %s) - 1), substr("rest", instr("rest", %s) + length(%s)) `+
+					`FROM "delimited_items" WHERE "rest" <> '') `+
+					`SELECT 1 FROM "delimited_items" WHERE trim("item") = CAST(%s AS TEXT))`,
+				list,
+				delimiter,
+				delimiter,
+				delimiter,
+				delimiter,
+				value,
+			), nil
+		}
+		return fmt.Sprintf(
+			"instr(%s || %s || %s, %s || CAST(%s AS TEXT) || %s) > 0",
+			delimiter,
+			list,
+			delimiter,
+			delimiter,
+			value,
+			delimiter,
+		), nil
+
+	case configuration.JSONDelimitedFormat:
+		return fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM json_each(%s) WHERE CAST(json_each.value AS TEXT) = CAST(%s AS TEXT))",
+			list,
+			value,
+		), nil
+	}
+
+	return "", &UnsupportedDelimitedFormatError{Dialect: sqliteDialect.Name(), Format: delimitedKey.Format}
+}
+
+func (sqliteDialect SQLiteDialect) Insert(
+	table string,
+	columns []string,
+	rows []string,
+	conflictPolicy ConflictPolicy,
+	keyColumns []string,
+) (string, error) {
+	return onConflict(table, columns, rows, conflictPolicy, keyColumns)
+}
+
+func (sqliteDialect SQLiteDialect) InsertDefaultValues(table string) string {
+	return "INSERT INTO " + table + " DEFAULT VALUES"
+}
+
+func (sqliteDialect SQLiteDialect) Returning(column string) string {
+	return " RETURNING " + column
+}
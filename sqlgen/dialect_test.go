@@ -0,0 +1,137 @@
+package sqlgen
+
+import (
+	"testing"
+	"time"
+
+	"entity-works/configuration"
+	"entity-works/internal/testdb"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialectsQuoteAndNumberPlaceholders(t *testing.T) {
+	mySQLDialect := NewMySQLDialect()
+	assert.Equal(t, "`order``items`", mySQLDialect.QuoteIdentifier("order`items"))
+	assert.Equal(t, `'it''s \\ here'`, mySQLDialect.QuoteString(`it's \ here`))
+	assert.Equal(t, "?", mySQLDialect.Placeholder(2))
+	assert.Equal(t, "FALSE", mySQLDialect.BooleanLiteral(false))
+	assert.Equal(t, "X'ff00'", mySQLDialect.BytesLiteral([]byte{0xff, 0x00}))
+
+	postgresDialect := NewPostgresDialect()
+	assert.Equal(t, `"order""items"`, postgresDialect.QuoteIdentifier(`order"items`))
+	assert.Equal(t, `'it''s \ here'`, postgresDialect.QuoteString(`it's \ here`))
+	assert.Equal(t, "$2", postgresDialect.Placeholder(2))
+	assert.Equal(t, "TRUE", postgresDialect.BooleanLiteral(true))
+	assert.Equal(t, " LIMIT 5", postgresDialect.Limit(5))
+	assert.Equal(t, `'\xff00'`, postgresDialect.BytesLiteral([]byte{0xff, 0x00}))
+
+	sqliteDialect := NewSQLiteDialect()
+	assert.Equal(t, "?", sqliteDialect.Placeholder(2))
+	assert.Equal(t, "0", sqliteDialect.BooleanLiteral(false))
+}
+
+func TestDialectsMatchDelimitedKeys(t *testing.T) {
+	textKey, err := configuration.NewDelimitedKey("TEXT", ";", true)
+	assert.Nil(t, err)
+	jsonKey, err := configuration.NewDelimitedKey("JSON", "", false)
+	assert.Nil(t, err)
+	arrayKey, err := configuration.NewDelimitedKey("ARRAY", "", false)
+	assert.Nil(t, err)
+
+	contains, err := NewMySQLDialect().DelimitedContains("list", "value", textKey)
+	assert.Nil(t, err)
+	assert.Equal(t, "FIND_IN_SET(CAST(value AS CHAR), REGEXP_REPLACE(REGEXP_REPLACE(list, ' *; *', ','), '^ +| +$', '')) > 0", contains)
+
+	contains, err = NewPostgresDialect().DelimitedContains("list", "value", arrayKey)
+	assert.Nil(t, err)
+	assert.Equal(t, "CAST(value AS TEXT) = ANY(CAST(list AS TEXT[]))", contains)
+
+	contains, err = NewSQLiteDialect().DelimitedContains("list", "value", jsonKey)
+	assert.Nil(t, err)
+	assert.Equal(t, "EXISTS (SELECT 1 FROM json_each(list) WHERE CAST(json_each.value AS TEXT) = CAST(value AS TEXT))", contains)
+
+	_, err = NewSQLiteDialect().DelimitedContains("list", "value", arrayKey)
+	assert.Equal(t, &UnsupportedDelimitedFormatError{Dialect: "sqlite", Format: configuration.ArrayDelimitedFormat}, err)
+}
+
+func TestSQLiteDialectTrimsOnlyAroundDelimiters(t *testing.T) {
+	textKey, err := configuration.NewDelimitedKey("TEXT", ";", true)
+	assert.Nil(t, err)
+	contains, err := NewSQLiteDialect().DelimitedContains("lists.list", "?", textKey)
+	assert.Nil(t, err)
+
+	database := testdb.Open(t, `CREATE TABLE lists (id INTEGER, list TEXT);
+INSERT INTO lists VALUES (1, ' a b ;c'), (2, 'ab; c'), (3, 'c ;  a b')`)
+
+	matches := func(value string) []int {
+		rows, err := database.Query("SELECT id FROM lists WHERE "+contains+" ORDER BY id", value)
+		assert.Nil(t, err)
+		defer rows.Close()
+
+		var ids []int
+		for rows.Next() {
+			var id int
+			assert.Nil(t, rows.Scan(&id))
+			ids = append(ids, id)
+		}
+
+		return ids
+	}
+
+	assert.Equal(t, []int{1, 3}, matches("a b"))
+	assert.Equal(t, []int{2}, matches("ab"))
+	assert.Equal(t, []int{1, 2, 3}, matches("c"))
+	assert.Empty(t, matches("b"))
+}
+
+func TestDialectsFormatTimestampLiterals(t *testing.T) {
+	timestamp := time.Date(2024, 1, 2, 3, 4, 5, 678000000, time.FixedZone("CET", 3600))
+
+	assert.Equal(t, "'2024-01-02 03:04:05.678'", NewMySQLDialect().TimestampLiteral(timestamp))
+	assert.Equal(t, "'2024-01-02 03:04:05.678+01:00'", NewPostgresDialect().TimestampLiteral(timestamp))
+	assert.Equal(t, "'2024-01-02 03:04:05.678'", NewSQLiteDialect().TimestampLiteral(timestamp))
+	assert.Equal(t, "'2024-01-02 03:04:05'", NewSQLiteDialect().TimestampLiteral(timestamp.Truncate(time.Second)))
+}
+
+func TestDialectsInsertDefaultValues(t *testing.T) {
+	assert.Equal(t, "INSERT INTO `tags` () VALUES ()", NewMySQLDialect().InsertDefaultValues("`tags`"))
+	assert.Equal(t, `INSERT INTO "tags" DEFAULT VALUES`, NewPostgresDialect().InsertDefaultValues(`"tags"`))
+	assert.Equal(t, `INSERT INTO "tags" DEFAULT VALUES`, NewSQLiteDialect().InsertDefaultValues(`"tags"`))
+}
+
+func TestDialectsApplyConflictPolicies(t *testing.T) {
+	columns := []string{`"id"`, `"name"`}
+	rows := []string{"($1, $2)", "($3, $4)"}
+	keyColumns := []string{`"id"`}
+
+	statement, err := NewPostgresDialect().Insert(`"regions"`, columns, rows, FailOnConflict, keyColumns)
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO "regions" ("id", "name") VALUES ($1, $2), ($3, $4)`, statement)
+
+	statement, err = NewSQLiteDialect().Insert(`"regions"`, columns, rows, SkipOnConflict, keyColumns)
+	assert.Nil(t, err)
+	assert.Equal(t, `INSERT INTO "regions" ("id", "name") VALUES ($1, $2), ($3, $4) ON CONFLICT ("id") DO NOTHING`, statement)
+
+	statement, err = NewPostgresDialect().Insert(`"regions"`, columns, rows, UpsertOnConflict, keyColumns)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		`INSERT INTO "regions" ("id", "name") VALUES ($1, $2), ($3, $4) ON CONFLICT ("id") DO UPDATE SET "name" = excluded."name"`,
+		statement,
+	)
+
+	statement, err = NewMySQLDialect().Insert("`regions`", []string{"`id`", "`name`"}, []string{"(?, ?)"}, SkipOnConflict, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "INSERT IGNORE INTO `regions` (`id`, `name`) VALUES (?, ?)", statement)
+
+	statement, err = NewMySQLDialect().Insert("`regions`", []string{"`id`", "`name`"}, []string{"(?, ?)"}, UpsertOnConflict, []string{"`id`"})
+	assert.Nil(t, err)
+	assert.Equal(t, "INSERT INTO `regions` (`id`, `name`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)", statement)
+
+	_, err = NewSQLiteDialect().Insert(`"regions"`, columns, rows, UpsertOnConflict, nil)
+	assert.Equal(t, &UpsertWithoutKeyError{Table: `"regions"`}, err)
+
+	_, err = NewMySQLDialect().Insert("`regions`", columns, rows, "replace", keyColumns)
+	assert.Equal(t, &UnknownConflictPolicyError{ConflictPolicy: "replace"}, err)
+}
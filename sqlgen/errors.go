@@ -0,0 +1,72 @@
+package sqlgen
+
+import (
+	"fmt"
+
+	"entity-works/configuration"
+)
+
+type UnknownEntityError struct {
+	Entity string
+}
+
+func (unknownEntityError *UnknownEntityError) Error() string {
+	return fmt.Sprintf("unknown entity %q", unknownEntityError.Entity)
+}
+
+type UnsupportedSelectionCriteriaError struct {
+	Element           configuration.ElementPath
+	SelectionCriteria configuration.SelectionCriteria
+}
+
+func (unsupportedSelectionCriteriaError *UnsupportedSelectionCriteriaError) Error() string {
+	return fmt.Sprintf(
+		"element %q uses selection criteria %T which has no SQL translation",
+		unsupportedSelectionCriteriaError.Element.String(),
+		unsupportedSelectionCriteriaError.SelectionCriteria,
+	)
+}
+
+type UnsupportedDelimitedFormatError struct {
+	Dialect string
+	Format  configuration.DelimitedFormat
+}
+
+func (unsupportedDelimitedFormatError *UnsupportedDelimitedFormatError) Error() string {
+	return fmt.Sprintf(
+		"%s cannot match %s delimited keys",
+		unsupportedDelimitedFormatError.Dialect,
+		unsupportedDelimitedFormatError.Format,
+	)
+}
+
+type UnknownConflictPolicyError struct {
+	ConflictPolicy ConflictPolicy
+}
+
+func (unknownConflictPolicyError *UnknownConflictPolicyError) Error() string {
+	return fmt.Sprintf("unknown conflict policy %q, expected fail, skip or upsert", unknownConflictPolicyError.ConflictPolicy)
+}
+
+type UpsertWithoutKeyError struct {
+	Table string
+}
+
+func (upsertWithoutKeyError *UpsertWithoutKeyError) Error() string {
+	return fmt.Sprintf("cannot upsert into %s without a primary key", upsertWithoutKeyError.Table)
+}
+
+type UnboundSharedParameterError struct {
+	Element   configuration.ElementPath
+	Entity    string
+	Parameter string
+}
+
+func (unboundSharedParameterError *UnboundSharedParameterError) Error() string {
+	return fmt.Sprintf(
+		"element %q shared into entity %q needs parameter %q, which that entity does not supply and the element's own entity does not default",
+		unboundSharedParameterError.Element.String(),
+		unboundSharedParameterError.Entity,
+		unboundSharedParameterError.Parameter,
+	)
+}
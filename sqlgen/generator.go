@@ -0,0 +1,520 @@
+package sqlgen
+
+import (
+	"fmt"
+	"strings"
+
+	"entity-works/configuration"
+)
+
+const recursionDepthColumn = "recursion_depth"
+
+type Query struct {
+	Element configuration.ElementPath
+	SQL     string
+	Args    []any
+}
+
+type Generator struct {
+	configuration *configuration.Configuration
+	dialect       Dialect
+	limit         int
+}
+
+func NewGenerator(configuration *configuration.Configuration, dialect Dialect) *Generator {
+	return &Generator{
+		configuration: configuration,
+		dialect:       dialect,
+	}
+}
+
+func (generator Generator) WithLimit(limit int) *Generator {
+	generator.limit = limit
+
+	return &generator
+}
+
+func (generator *Generator) Dialect() Dialect {
+	return generator.dialect
+}
+
+func (generator *Generator) Generate(entityName string, parameters map[string]any) ([]Query, error) {
+	entity, exists := generator.configuration.Entity(entityName)
+	if !exists {
+		return nil, &UnknownEntityError{Entity: entityName}
+	}
+
+	values, err := entity.BindParameters(parameters)
+	if err != nil {
+		return nil, fmt.Errorf("binding parameters of entity %q: %w", entityName, err)
+	}
+
+	var queries []Query
+	dependencyGraph := generator.configuration.DependencyGraph()
+	for _, elementPath := range dependencyGraph.Order() {
+		if elementPath.Entity != entityName {
+			continue
+		}
+
+		query, err := generator.GenerateElement(elementPath, values)
+		if err != nil {
+			return nil, err
+		}
+
+		queries = append(queries, query)
+	}
+
+	return queries, nil
+}
+
+func (generator *Generator) GenerateElement(elementPath configuration.ElementPath, values map[string]any) (Query, error) {
+	element, exists := generator.configuration.Element(elementPath)
+	if !exists {
+		return Query{}, &configuration.UnknownElementError{Element: elementPath.String()}
+	}
+
+	bindings := &parameterBindings{
+		configuration: generator.configuration,
+		entity:        elementPath.Entity,
+		values:        values,
+		shared:        make(map[string]map[string]any),
+	}
+
+	queryBuilder := &queryBuilder{}
+	if err := generator.selectRows(queryBuilder, element, nil, bindings); err != nil {
+		return Query{}, fmt.Errorf("generating query for element %q: %w", elementPath.String(), err)
+	}
+
+	if generator.limit > 0 {
+		queryBuilder.write(generator.dialect.Limit(generator.limit))
+	}
+
+	return Query{
+		Element: elementPath,
+		SQL:     queryBuilder.sql.String(),
+		Args:    queryBuilder.args,
+	}, nil
+}
+
+func (generator *Generator) GenerateKeyLookup(resource configuration.Resource, columns []configuration.ColumnRef, tuples [][]any) Query {
+	queryBuilder := &queryBuilder{}
+	queryBuilder.write("SELECT * FROM ", QuoteTable(generator.dialect, resource.Table()), " WHERE ")
+	if len(tuples) == 0 {
+		queryBuilder.write(generator.dialect.BooleanLiteral(false))
+	}
+
+	if len(columns) == 1 && len(tuples) > 0 {
+		placeholders := make([]string, 0, len(tuples))
+		for _, tuple := range tuples {
+			queryBuilder.args = append(queryBuilder.args, tuple[0])
+			placeholders = append(placeholders, generator.dialect.Placeholder(len(queryBuilder.args)))
+		}
+		queryBuilder.write(QuoteColumn(generator.dialect, columns[0]), " IN (", strings.Join(placeholders, ", "), ")")
+	} else {
+		conditions := make([]string, 0, len(tuples))
+		for _, tuple := range tuples {
+			matches := make([]string, 0, len(tuple))
+			for index, value := range tuple {
+				queryBuilder.args = append(queryBuilder.args, value)
+				matches = append(matches, QuoteColumn(generator.dialect, columns[index])+" = "+generator.dialect.Placeholder(len(queryBuilder.args)))
+			}
+			conditions = append(conditions, "("+strings.Join(matches, " AND ")+")")
+		}
+		queryBuilder.write(strings.Join(conditions, " OR "))
+	}
+
+	return Query{SQL: queryBuilder.sql.String(), Args: queryBuilder.args}
+}
+
+func (generator *Generator) selectRows(
+	queryBuilder *queryBuilder,
+	element *configuration.Element,
+	columns []configuration.ColumnRef,
+	bindings *parameterBindings,
+) error {
+	element, err := generator.resolveShares(element)
+	if err != nil {
+		return err
+	}
+
+	selectList := "*"
+	if len(columns) > 0 {
+		selectList = generator.columnList(columns)
+	}
+
+	queryBuilder.write("SELECT ", selectList, " FROM ", QuoteTable(generator.dialect, element.Resource().Table()))
+
+	if recursion, recursive := element.Recursion(); recursive {
+		return generator.recursiveCondition(queryBuilder, element, recursion, bindings)
+	}
+
+	return generator.where(queryBuilder, element, bindings)
+}
+
+func (generator *Generator) resolveShares(element *configuration.Element) (*configuration.Element, error) {
+	sharedElementPath, shares := element.Shares()
+	if !shares {
+		return element, nil
+	}
+
+	sharedElement, exists := generator.configuration.Element(sharedElementPath)
+	if !exists {
+		return nil, &configuration.UnknownElementError{Element: sharedElementPath.String()}
+	}
+
+	return sharedElement, nil
+}
+
+func (generator *Generator) where(queryBuilder *queryBuilder, element *configuration.Element, bindings *parameterBindings) error {
+	switch selectionCriteria := element.SelectionCriteria().(type) {
+	case nil:
+		return nil
+
+	case *configuration.CustomSelectionCriteria:
+		values, err := bindings.bind(element, selectionCriteria.Template())
+		if err != nil {
+			return err
+		}
+
+		criteria, args, err := selectionCriteria.Template().Render(values, func(position int) string {
+			return generator.dialect.Placeholder(len(queryBuilder.args) + position)
+		})
+		if err != nil {
+			return err
+		}
+
+		queryBuilder.write(" WHERE ", strings.TrimSpace(criteria))
+		queryBuilder.args = append(queryBuilder.args, args...)
+		return nil
+
+	case *configuration.RelatedSelectionCriteria:
+		queryBuilder.write(" WHERE ")
+		relations := selectionCriteria.Relations()
+		directions := selectionCriteria.Directions()
+		if len(relations) == 0 {
+			queryBuilder.write(generator.dialect.BooleanLiteral(false))
+		}
+		for index, upstream := range selectionCriteria.Elements() {
+			if index > 0 {
+				queryBuilder.write(" OR ")
+			}
+
+			if err := generator.relatedCondition(queryBuilder, upstream, relations[index], directions[index], bindings); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case *configuration.IndexedSelectionCriteria:
+		queryBuilder.write(" WHERE ")
+		mappings := selectionCriteria.Mappings()
+		if len(mappings) == 0 {
+			queryBuilder.write(generator.dialect.BooleanLiteral(false))
+		}
+		for index, mapping := range mappings {
+			if index > 0 {
+				queryBuilder.write(" OR ")
+			}
+
+			if err := generator.inSubquery(queryBuilder, mapping.Columns, mapping.Upstream, mapping.UpstreamColumns, bindings); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return &UnsupportedSelectionCriteriaError{Element: element.Path(), SelectionCriteria: element.SelectionCriteria()}
+}
+
+func (generator *Generator) relatedCondition(
+	queryBuilder *queryBuilder,
+	upstream *configuration.Element,
+	relation configuration.Relation,
+	direction configuration.RelationDirection,
+	bindings *parameterBindings,
+) error {
+	if delimitedKey, isDelimited := relation.DelimitedKey(); isDelimited {
+		return generator.delimitedCondition(queryBuilder, upstream, relation, direction, delimitedKey, bindings)
+	}
+
+	if direction == configuration.ReferencingDirection {
+		return generator.inSubquery(queryBuilder, relation.FromKeys(), upstream, relation.ToKeys(), bindings)
+	}
+
+	return generator.inSubquery(queryBuilder, relation.ToKeys(), upstream, relation.FromKeys(), bindings)
+}
+
+func (generator *Generator) delimitedCondition(
+	queryBuilder *queryBuilder,
+	upstream *configuration.Element,
+	relation configuration.Relation,
+	direction configuration.RelationDirection,
+	delimitedKey configuration.DelimitedKey,
+	bindings *parameterBindings,
+) error {
+	listColumn := relation.FromKeys()[0]
+	valueColumn := relation.ToKeys()[0]
+	alias := queryBuilder.alias("upstream")
+
+	upstreamColumn := listColumn
+	list := QuoteIdentifiers(generator.dialect, alias, listColumn.Column)
+	value := QuoteColumn(generator.dialect, valueColumn)
+	if direction == configuration.ReferencingDirection {
+		upstreamColumn = valueColumn
+		list = QuoteColumn(generator.dialect, listColumn)
+		value = QuoteIdentifiers(generator.dialect, alias, valueColumn.Column)
+	}
+
+	contains, err := generator.dialect.DelimitedContains(list, value, delimitedKey)
+	if err != nil {
+		return fmt.Errorf("matching relation %q: %w", relation.QualifiedName(), err)
+	}
+
+	queryBuilder.write("EXISTS (SELECT 1 FROM (")
+	if err := generator.selectRows(queryBuilder, upstream, []configuration.ColumnRef{upstreamColumn}, bindings); err != nil {
+		return err
+	}
+	queryBuilder.write(") AS ", generator.dialect.QuoteIdentifier(alias), " WHERE ", contains, ")")
+
+	return nil
+}
+
+func (generator *Generator) inSubquery(
+	queryBuilder *queryBuilder,
+	columns []configuration.ColumnRef,
+	upstream *configuration.Element,
+	upstreamColumns []configuration.ColumnRef,
+	bindings *parameterBindings,
+) error {
+	queryBuilder.write(generator.keyList(columns), " IN (")
+
+	if err := generator.selectRows(queryBuilder, upstream, upstreamColumns, bindings); err != nil {
+		return err
+	}
+
+	queryBuilder.write(")")
+	return nil
+}
+
+func (generator *Generator) recursiveCondition(
+	queryBuilder *queryBuilder,
+	element *configuration.Element,
+	recursion configuration.Recursion,
+	bindings *parameterBindings,
+) error {
+	keyColumns := recursion.Relation().ToKeys()
+	queryBuilder.write(" WHERE ", generator.keyList(keyColumns), " IN (WITH RECURSIVE ")
+
+	var names []string
+	for index, direction := range recursion.Directions() {
+		if index > 0 {
+			queryBuilder.write(", ")
+		}
+
+		name := queryBuilder.alias(strings.ToLower(string(direction)))
+		if err := generator.recursiveTable(queryBuilder, element, recursion, direction, name, bindings); err != nil {
+			return err
+		}
+		names = append(names, name)
+	}
+
+	for index, name := range names {
+		if index > 0 {
+			queryBuilder.write(" UNION")
+		}
+
+		queryBuilder.write(" SELECT ", generator.aliasedColumnList(name, keyColumns), " FROM ", generator.dialect.QuoteIdentifier(name))
+	}
+	queryBuilder.write(")")
+
+	return nil
+}
+
+func (generator *Generator) recursiveTable(
+	queryBuilder *queryBuilder,
+	element *configuration.Element,
+	recursion configuration.Recursion,
+	direction configuration.RecursionDirection,
+	name string,
+	bindings *parameterBindings,
+) error {
+	relation := recursion.Relation()
+	keyColumns := relation.ToKeys()
+	table := QuoteTable(generator.dialect, element.Resource().Table())
+	depth := generator.dialect.QuoteIdentifier(recursionDepthColumn)
+	seed := queryBuilder.alias("seed")
+
+	queryBuilder.write(generator.dialect.QuoteIdentifier(name), " AS (SELECT ", generator.aliasedColumnList(seed, keyColumns))
+	if recursion.MaxDepth() > 0 {
+		queryBuilder.write(", 0 AS ", depth)
+	}
+	queryBuilder.write(" FROM (SELECT ", generator.columnList(keyColumns), " FROM ", table)
+	if err := generator.where(queryBuilder, element, bindings); err != nil {
+		return err
+	}
+	queryBuilder.write(") AS ", generator.dialect.QuoteIdentifier(seed))
+
+	step := queryBuilder.alias("step")
+	previous := queryBuilder.alias("previous")
+	queryBuilder.write(" UNION SELECT ", generator.aliasedColumnList(step, keyColumns))
+	if recursion.MaxDepth() > 0 {
+		queryBuilder.write(", ", QuoteIdentifiers(generator.dialect, previous, recursionDepthColumn), " + 1")
+	}
+	queryBuilder.write(" FROM ", table, " AS ", generator.dialect.QuoteIdentifier(step))
+
+	previousCondition := ""
+	if direction == configuration.AncestorsRecursion {
+		referencing := queryBuilder.alias("referencing")
+		condition, err := generator.joinCondition(relation, referencing, step)
+		if err != nil {
+			return err
+		}
+
+		queryBuilder.write(" JOIN ", table, " AS ", generator.dialect.QuoteIdentifier(referencing), " ON ", condition)
+		previousCondition = generator.equalColumns(referencing, previous, keyColumns)
+	} else {
+		condition, err := generator.joinCondition(relation, step, previous)
+		if err != nil {
+			return err
+		}
+
+		previousCondition = condition
+	}
+	queryBuilder.write(" JOIN ", generator.dialect.QuoteIdentifier(name), " AS ", generator.dialect.QuoteIdentifier(previous), " ON ", previousCondition)
+
+	if recursion.MaxDepth() > 0 {
+		queryBuilder.write(" WHERE ", QuoteIdentifiers(generator.dialect, previous, recursionDepthColumn), fmt.Sprintf(" < %d", recursion.MaxDepth()))
+	}
+	queryBuilder.write(")")
+
+	return nil
+}
+
+func (generator *Generator) joinCondition(relation configuration.Relation, referencing string, referenced string) (string, error) {
+	if delimitedKey, isDelimited := relation.DelimitedKey(); isDelimited {
+		contains, err := generator.dialect.DelimitedContains(
+			QuoteIdentifiers(generator.dialect, referencing, relation.FromKeys()[0].Column),
+			QuoteIdentifiers(generator.dialect, referenced, relation.ToKeys()[0].Column),
+			delimitedKey,
+		)
+		if err != nil {
+			return "", fmt.Errorf("matching relation %q: %w", relation.QualifiedName(), err)
+		}
+
+		return contains, nil
+	}
+
+	fromKeys := relation.FromKeys()
+	toKeys := relation.ToKeys()
+	conditions := make([]string, 0, len(fromKeys))
+	for index := range fromKeys {
+		conditions = append(conditions, QuoteIdentifiers(generator.dialect, referencing, fromKeys[index].Column)+
+			" = "+QuoteIdentifiers(generator.dialect, referenced, toKeys[index].Column))
+	}
+
+	return strings.Join(conditions, " AND "), nil
+}
+
+func (generator *Generator) equalColumns(alias string, otherAlias string, columns []configuration.ColumnRef) string {
+	conditions := make([]string, 0, len(columns))
+	for _, column := range columns {
+		conditions = append(conditions, QuoteIdentifiers(generator.dialect, alias, column.Column)+
+			" = "+QuoteIdentifiers(generator.dialect, otherAlias, column.Column))
+	}
+
+	return strings.Join(conditions, " AND ")
+}
+
+func (generator *Generator) keyList(columns []configuration.ColumnRef) string {
+	if len(columns) == 1 {
+		return QuoteColumn(generator.dialect, columns[0])
+	}
+
+	return "(" + generator.columnList(columns) + ")"
+}
+
+func (generator *Generator) aliasedColumnList(alias string, columns []configuration.ColumnRef) string {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		names = append(names, QuoteIdentifiers(generator.dialect, alias, column.Column))
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func (generator *Generator) columnList(columns []configuration.ColumnRef) string {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		names = append(names, QuoteColumn(generator.dialect, column))
+	}
+
+	return strings.Join(names, ", ")
+}
+
+type parameterBindings struct {
+	configuration *configuration.Configuration
+	entity        string
+	values        map[string]any
+	shared        map[string]map[string]any
+}
+
+func (parameterBindings *parameterBindings) bind(element *configuration.Element, template configuration.CriteriaTemplate) (map[string]any, error) {
+	entityName := element.Path().Entity
+	if entityName == parameterBindings.entity {
+		return parameterBindings.values, nil
+	}
+
+	defaults, bound := parameterBindings.shared[entityName]
+	if !bound {
+		entity, exists := parameterBindings.configuration.Entity(entityName)
+		if !exists {
+			return nil, &UnknownEntityError{Entity: entityName}
+		}
+
+		defaults = make(map[string]any)
+		for name, parameter := range entity.Parameters() {
+			if defaultValue, hasDefault := parameter.Default(); hasDefault {
+				defaults[name] = defaultValue
+			}
+		}
+		parameterBindings.shared[entityName] = defaults
+	}
+
+	values := make(map[string]any, len(template.Parameters()))
+	for _, parameterName := range template.Parameters() {
+		if value, exists := parameterBindings.values[parameterName]; exists {
+			values[parameterName] = value
+			continue
+		}
+
+		defaultValue, exists := defaults[parameterName]
+		if !exists {
+			return nil, &UnboundSharedParameterError{
+				Element:   element.Path(),
+				Entity:    parameterBindings.entity,
+				Parameter: parameterName,
+			}
+		}
+		values[parameterName] = defaultValue
+	}
+
+	return values, nil
+}
+
+type queryBuilder struct {
+	sql     strings.Builder
+	args    []any
+	aliases int
+}
+
+func (queryBuilder *queryBuilder) alias(prefix string) string {
+	queryBuilder.aliases++
+
+	return fmt.Sprintf("%s%d", prefix, queryBuilder.aliases)
+}
+
+func (queryBuilder *queryBuilder) write(parts ...string) {
+	for _, part := range parts {
+		queryBuilder.sql.WriteString(part)
+	}
+}
@@ -0,0 +1,466 @@
+package sqlgen
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"entity-works/configuration"
+	"entity-works/internal/testdb"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const shopYmlConfiguration = `Name: Shop
+Resources:
+  Regions:
+    TableName: regions
+    PrimaryKey:
+      - regions.region
+  Customers:
+    TableName: customers
+    PrimaryKey:
+      - customers.id
+    Index:
+      Region:
+        - customers.region
+  Orders:
+    TableName: orders
+    PrimaryKey:
+      - orders.id
+    ForeignKeys:
+      - Type: NORMAL
+        Key: orders.customer_id
+        ResourceName: Customers
+        ForeignKey: customers.id
+  Shipments:
+    TableName: shipments
+    PrimaryKey:
+      - shipments.id
+    ForeignKeys:
+      - Type: NORMAL
+        Key:
+          - shipments.order_id
+          - shipments.tenant_id
+        ResourceName: Orders
+        ForeignKey:
+          - orders.id
+          - orders.tenant_id
+Entities:
+  CoreProduct:
+    Parameters:
+      region:
+        Required: true
+    Components:
+      Customers:
+        Elements:
+          Regions:
+            Resource: Regions
+            SelectionCriteria:
+              Type: Custom
+              Criteria: |
+                region = "{{region}}"
+          Customers:
+            Resource: Customers
+            SelectionCriteria:
+              Type: Index
+              Index: Region
+              Elements:
+                - Regions
+          Everything:
+            Resource: Regions
+      Orders:
+        Elements:
+          Customers:
+            Shares: CoreProduct::Customers::Customers
+          Orders:
+            Resource: Orders
+            SelectionCriteria:
+              Type: Related
+              Elements:
+                - Customers
+          Shipments:
+            Resource: Shipments
+            SelectionCriteria:
+              Type: Related
+              Elements:
+                - Orders
+`
+
+func newShopGenerator(t *testing.T, ymlConfiguration string) *Generator {
+	return newShopGeneratorWithDialect(t, ymlConfiguration, NewSQLiteDialect())
+}
+
+func newShopGeneratorWithDialect(t *testing.T, ymlConfiguration string, dialect Dialect) *Generator {
+	loadedConfiguration, err := configuration.LoadReader(strings.NewReader(ymlConfiguration))
+	assert.Nil(t, err)
+
+	return NewGenerator(loadedConfiguration, dialect)
+}
+
+func TestGeneratorEmitsOneQueryPerElementInDependencyOrder(t *testing.T) {
+	queries, err := newShopGenerator(t, shopYmlConfiguration).Generate("CoreProduct", map[string]any{"region": "EU"})
+	assert.Nil(t, err)
+
+	var elements []string
+	for _, query := range queries {
+		elements = append(elements, query.Element.String())
+	}
+	assert.Equal(
+		t,
+		[]string{
+			"CoreProduct::Customers::Regions",
+			"CoreProduct::Customers::Customers",
+			"CoreProduct::Customers::Everything",
+			"CoreProduct::Orders::Customers",
+			"CoreProduct::Orders::Orders",
+			"CoreProduct::Orders::Shipments",
+		},
+		elements,
+	)
+
+	assert.Equal(t, `SELECT * FROM "regions" WHERE region = ?`, queries[0].SQL)
+	assert.Equal(t, []any{"EU"}, queries[0].Args)
+	assert.Equal(t, `SELECT * FROM "customers" WHERE "customers"."region" IN (SELECT "regions"."region" FROM "regions" WHERE region = ?)`, queries[1].SQL)
+	assert.Equal(t, `SELECT * FROM "regions"`, queries[2].SQL)
+	assert.Empty(t, queries[2].Args)
+	assert.Equal(t, queries[1].SQL, queries[3].SQL)
+	assert.Equal(
+		t,
+		`SELECT * FROM "orders" WHERE "orders"."customer_id" IN `+
+			`(SELECT "customers"."id" FROM "customers" WHERE "customers"."region" IN `+
+			`(SELECT "regions"."region" FROM "regions" WHERE region = ?))`,
+		queries[4].SQL,
+	)
+	assert.Equal(
+		t,
+		`SELECT * FROM "shipments" WHERE ("shipments"."order_id", "shipments"."tenant_id") IN `+
+			`(SELECT "orders"."id", "orders"."tenant_id" FROM "orders" WHERE "orders"."customer_id" IN `+
+			`(SELECT "customers"."id" FROM "customers" WHERE "customers"."region" IN `+
+			`(SELECT "regions"."region" FROM "regions" WHERE region = ?)))`,
+		queries[5].SQL,
+	)
+	assert.Equal(t, []any{"EU"}, queries[5].Args)
+}
+
+func TestGeneratorFollowsRelationsInBothDirections(t *testing.T) {
+	generator := newShopGenerator(t, shopYmlConfiguration+`      Reverse:
+        Elements:
+          Orders:
+            Resource: Orders
+            SelectionCriteria:
+              Type: Custom
+              Criteria: orders.total > 100
+          Customers:
+            Resource: Customers
+            SelectionCriteria:
+              Type: Related
+              Elements:
+                - Orders
+`)
+
+	query, err := generator.GenerateElement(
+		configuration.ElementPath{Entity: "CoreProduct", Component: "Reverse", Element: "Customers"},
+		nil,
+	)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		`SELECT * FROM "customers" WHERE "customers"."id" IN (SELECT "orders"."customer_id" FROM "orders" WHERE orders.total > 100)`,
+		query.SQL,
+	)
+}
+
+func TestGeneratorBindsParametersInsteadOfInterpolating(t *testing.T) {
+	queries, err := newShopGenerator(t, shopYmlConfiguration).Generate("CoreProduct", map[string]any{"region": `EU" OR "1" = "1`})
+	assert.Nil(t, err)
+
+	assert.Equal(t, `SELECT * FROM "regions" WHERE region = ?`, queries[0].SQL)
+	assert.Equal(t, []any{`EU" OR "1" = "1`}, queries[0].Args)
+}
+
+func TestGeneratorReportsParameterAndEntityErrors(t *testing.T) {
+	generator := newShopGenerator(t, shopYmlConfiguration)
+
+	_, err := generator.Generate("CoreProduct", nil)
+	var missingParameterError *configuration.MissingParameterError
+	assert.True(t, errors.As(err, &missingParameterError))
+
+	_, err = generator.Generate("Reporting", nil)
+	var unknownEntityError *UnknownEntityError
+	assert.True(t, errors.As(err, &unknownEntityError))
+}
+
+const preferencesYmlConfiguration = `Name: Shop
+Resources:
+  Categories:
+    TableName: categories
+    PrimaryKey:
+      - categories.id
+  CustomerPreferences:
+    TableName: customer_preferences
+    ForeignKeys:
+      - Type: DELIMITED
+        Format: %s
+        Key: customer_preferences.fav_category_ids
+        ResourceName: Categories
+        ForeignKey: categories.id
+Entities:
+  CoreProduct:
+    Components:
+      Preferences:
+        Elements:
+          Preferences:
+            Resource: CustomerPreferences
+            SelectionCriteria:
+              Type: Custom
+              Criteria: customer_id = 7
+          Categories:
+            Resource: Categories
+            SelectionCriteria:
+              Type: Related
+              Elements:
+                - Preferences
+          Favourites:
+            Resource: CustomerPreferences
+            SelectionCriteria:
+              Type: Related
+              Elements:
+                - Categories
+`
+
+func TestGeneratorMatchesDelimitedRelationsThroughTheDialect(t *testing.T) {
+	generator := newShopGeneratorWithDialect(t, fmt.Sprintf(preferencesYmlConfiguration, "TEXT"), NewPostgresDialect())
+
+	queries, err := generator.Generate("CoreProduct", nil)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		`SELECT * FROM "categories" WHERE EXISTS (SELECT 1 FROM `+
+			`(SELECT "customer_preferences"."fav_category_ids" FROM "customer_preferences" WHERE customer_id = 7) AS "upstream1" `+
+			`WHERE CAST("categories"."id" AS TEXT) = ANY(string_to_array("upstream1"."fav_category_ids", ',')))`,
+		queries[1].SQL,
+	)
+	assert.Equal(
+		t,
+		`SELECT * FROM "customer_preferences" WHERE EXISTS (SELECT 1 FROM `+
+			`(SELECT "categories"."id" FROM "categories" WHERE EXISTS (SELECT 1 FROM `+
+			`(SELECT "customer_preferences"."fav_category_ids" FROM "customer_preferences" WHERE customer_id = 7) AS "upstream2" `+
+			`WHERE CAST("categories"."id" AS TEXT) = ANY(string_to_array("upstream2"."fav_category_ids", ',')))) AS "upstream1" `+
+			`WHERE CAST("upstream1"."id" AS TEXT) = ANY(string_to_array("customer_preferences"."fav_category_ids", ',')))`,
+		queries[2].SQL,
+	)
+}
+
+func TestGeneratorRejectsDelimitedFormatsTheDialectCannotMatch(t *testing.T) {
+	generator := newShopGeneratorWithDialect(t, fmt.Sprintf(preferencesYmlConfiguration, "ARRAY"), NewMySQLDialect())
+
+	_, err := generator.Generate("CoreProduct", nil)
+	var unsupportedDelimitedFormatError *UnsupportedDelimitedFormatError
+	assert.True(t, errors.As(err, &unsupportedDelimitedFormatError))
+	assert.Equal(t, "mysql", unsupportedDelimitedFormatError.Dialect)
+	assert.Equal(t, configuration.ArrayDelimitedFormat, unsupportedDelimitedFormatError.Format)
+}
+
+func TestGeneratorNumbersPlaceholdersAcrossTheWholeQuery(t *testing.T) {
+	ymlConfiguration := strings.Replace(shopYmlConfiguration, "    Parameters:\n", "    Parameters:\n      from: {}\n      until: {}\n", 1)
+	generator := newShopGeneratorWithDialect(t, ymlConfiguration+`      Windows:
+        Elements:
+          Orders:
+            Resource: Orders
+            SelectionCriteria:
+              Type: Custom
+              Criteria: created_at BETWEEN {{from}} AND {{until}}
+          Shipments:
+            Resource: Shipments
+            SelectionCriteria:
+              Type: Related
+              Elements:
+                - Orders
+`, NewPostgresDialect())
+
+	query, err := generator.WithLimit(10).GenerateElement(
+		configuration.ElementPath{Entity: "CoreProduct", Component: "Windows", Element: "Shipments"},
+		map[string]any{"from": "2024-01-01", "until": "2024-02-01"},
+	)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		`SELECT * FROM "shipments" WHERE ("shipments"."order_id", "shipments"."tenant_id") IN `+
+			`(SELECT "orders"."id", "orders"."tenant_id" FROM "orders" WHERE created_at BETWEEN $1 AND $2) LIMIT 10`,
+		query.SQL,
+	)
+	assert.Equal(t, []any{"2024-01-01", "2024-02-01"}, query.Args)
+}
+
+const categoriesYmlConfiguration = `Name: Shop
+Resources:
+  Categories:
+    TableName: categories
+    PrimaryKey:
+      - categories.id
+    ForeignKeys:
+      - Type: NORMAL
+        Name: Parent
+        Key: categories.parent_id
+        ResourceName: Categories
+        ForeignKey: categories.id
+Entities:
+  Catalogue:
+    Components:
+      Categories:
+        Elements:
+          Books:
+            Resource: Categories
+            SelectionCriteria:
+              Type: Custom
+              Criteria: name = 'Books'
+          Children:
+            Resource: Categories
+            SelectionCriteria:
+              Type: Related
+              Elements:
+                - Books
+          Parents:
+            Resource: Categories
+            SelectionCriteria:
+              Type: Related
+              Direction: Referenced
+              Elements:
+                - Books
+          Lineage:
+            Resource: Categories
+            SelectionCriteria:
+              Type: Custom
+              Criteria: name = 'Books'
+              Recursive:
+                Direction: Ancestors
+                MaxDepth: 2
+          Family:
+            Resource: Categories
+            SelectionCriteria:
+              Type: Custom
+              Criteria: name = 'Books'
+              Recursive:
+                Direction: Both
+`
+
+func TestGeneratorFollowsSelfRelationsInTheConfiguredDirection(t *testing.T) {
+	generator := newShopGenerator(t, categoriesYmlConfiguration)
+
+	children, err := generator.GenerateElement(configuration.ElementPath{Entity: "Catalogue", Component: "Categories", Element: "Children"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		`SELECT * FROM "categories" WHERE "categories"."parent_id" IN (SELECT "categories"."id" FROM "categories" WHERE name = 'Books')`,
+		children.SQL,
+	)
+
+	parents, err := generator.GenerateElement(configuration.ElementPath{Entity: "Catalogue", Component: "Categories", Element: "Parents"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		`SELECT * FROM "categories" WHERE "categories"."id" IN (SELECT "categories"."parent_id" FROM "categories" WHERE name = 'Books')`,
+		parents.SQL,
+	)
+}
+
+func TestGeneratorFollowsRecursionWithARecursiveCommonTableExpression(t *testing.T) {
+	generator := newShopGenerator(t, categoriesYmlConfiguration)
+
+	lineage, err := generator.GenerateElement(configuration.ElementPath{Entity: "Catalogue", Component: "Categories", Element: "Lineage"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(
+		t,
+		`SELECT * FROM "categories" WHERE "categories"."id" IN (WITH RECURSIVE "ancestors1" AS (`+
+			`SELECT "seed2"."id", 0 AS "recursion_depth" FROM (SELECT "categories"."id" FROM "categories" WHERE name = 'Books') AS "seed2" `+
+			`UNION SELECT "step3"."id", "previous4"."recursion_depth" + 1 FROM "categories" AS "step3" `+
+			`JOIN "categories" AS "referencing5" ON "referencing5"."parent_id" = "step3"."id" `+
+			`JOIN "ancestors1" AS "previous4" ON "referencing5"."id" = "previous4"."id" WHERE "previous4"."recursion_depth" < 2) `+
+			`SELECT "ancestors1"."id" FROM "ancestors1")`,
+		lineage.SQL,
+	)
+
+	database := testdb.Open(t, `
+CREATE TABLE categories (id INTEGER PRIMARY KEY, parent_id INTEGER, name TEXT);
+INSERT INTO categories VALUES (1, NULL, 'All'), (2, 1, 'Books'), (3, 2, 'Fiction'), (4, 2, 'Poetry'), (5, 3, 'Crime'), (6, 1, 'Music');
+`)
+
+	assert.Equal(t, []string{"All", "Books"}, queryNames(t, database, lineage))
+
+	family, err := generator.GenerateElement(configuration.ElementPath{Entity: "Catalogue", Component: "Categories", Element: "Family"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"All", "Books", "Fiction", "Poetry", "Crime"}, queryNames(t, database, family))
+}
+
+func queryNames(t *testing.T, database *sql.DB, query Query) []string {
+	rows, err := database.Query("SELECT name FROM ("+query.SQL+") AS selected ORDER BY id", query.Args...)
+	assert.Nil(t, err)
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		assert.Nil(t, rows.Scan(&name))
+		names = append(names, name)
+	}
+
+	return names
+}
+
+func TestGeneratorBindsParametersOfSharedElements(t *testing.T) {
+	const reportingEntity = `  Reporting:
+    Components:
+      Regions:
+        Elements:
+          Regions:
+            Shares: CoreProduct::Customers::Regions
+`
+	const reportingEntityWithRegion = `  Reporting:
+    Parameters:
+      region:
+        Required: true
+    Components:
+      Regions:
+        Elements:
+          Regions:
+            Shares: CoreProduct::Customers::Regions
+`
+	reportingElement := configuration.ElementPath{Entity: "Reporting", Component: "Regions", Element: "Regions"}
+	withDefault := strings.Replace(shopYmlConfiguration, "        Required: true\n", "        Default: EU\n", 1)
+
+	generator := newShopGenerator(t, strings.Replace(withDefault, "\nEntities:\n", "\nEntities:\n"+reportingEntityWithRegion, 1))
+	query, err := generator.GenerateElement(reportingElement, map[string]any{"region": "US"})
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT * FROM "regions" WHERE region = ?`, query.SQL)
+	assert.Equal(t, []any{"US"}, query.Args)
+
+	generator = newShopGenerator(t, strings.Replace(withDefault, "\nEntities:\n", "\nEntities:\n"+reportingEntity, 1))
+	query, err = generator.GenerateElement(reportingElement, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []any{"EU"}, query.Args)
+
+	generator = newShopGenerator(t, strings.Replace(shopYmlConfiguration, "\nEntities:\n", "\nEntities:\n"+reportingEntity, 1))
+	_, err = generator.Generate("Reporting", nil)
+	var unboundSharedParameterError *UnboundSharedParameterError
+	assert.True(t, errors.As(err, &unboundSharedParameterError))
+	assert.Equal(t, "CoreProduct::Customers::Regions", unboundSharedParameterError.Element.String())
+	assert.Equal(t, "Reporting", unboundSharedParameterError.Entity)
+	assert.Equal(t, "region", unboundSharedParameterError.Parameter)
+}
+
+func TestGeneratorLooksUpRowsByKey(t *testing.T) {
+	generator := newShopGenerator(t, shopYmlConfiguration)
+	customers, _ := generator.configuration.Resource("Customers")
+
+	query := generator.GenerateKeyLookup(customers, customers.PrimaryKey(), [][]any{{1}, {2}})
+	assert.Equal(t, `SELECT * FROM "customers" WHERE "customers"."id" IN (?, ?)`, query.SQL)
+	assert.Equal(t, []any{1, 2}, query.Args)
+
+	compositeKey := []configuration.ColumnRef{{Table: "customers", Column: "id"}, {Table: "customers", Column: "region"}}
+	query = generator.GenerateKeyLookup(customers, compositeKey, [][]any{{1, "EU"}, {2, "US"}})
+	assert.Equal(t, `SELECT * FROM "customers" WHERE ("customers"."id" = ? AND "customers"."region" = ?) OR ("customers"."id" = ? AND "customers"."region" = ?)`, query.SQL)
+	assert.Equal(t, []any{1, "EU", 2, "US"}, query.Args)
+}